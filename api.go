@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	requestTimeout     = 20 * time.Second
+	maxRequestAttempts = 4
+	minRetryDelay      = 500 * time.Millisecond
+	maxRetryDelay      = 10 * time.Second
+	maxRetryAfter      = 2 * time.Minute
+)
+
+type RiotError struct {
+	StatusCode int
+	ErrorCode  string
+	Message    string
+}
+
+type DecodeError struct {
+	Host string
+	Err  error
+}
+
+type riotErrorBody struct {
+	HttpStatus int    `json:"httpStatus"`
+	ErrorCode  string `json:"errorCode"`
+	Message    string `json:"message"`
+	Error      string `json:"error"`
+	Status     struct {
+		Message    string `json:"message"`
+		StatusCode int    `json:"status_code"`
+	} `json:"status"`
+}
+
+var riotErrorDescriptions = map[string]string{
+	"auth_failure":               "Your username or password is incorrect",
+	"rate_limited":               "Too many login attempts, please try again later",
+	"multifactor_attempt_failed": "The multi-factor authentication code is incorrect",
+	"BAD_CLAIMS":                 "Your session has expired, please log in again",
+	"SCHEDULED_DOWNTIME":         "Riot servers are under maintenance",
+}
+
+func (e *RiotError) Error() string {
+	var parts []string
+	if e.StatusCode != 0 {
+		parts = append(parts, fmt.Sprintf("HTTP %d", e.StatusCode))
+	}
+	if e.ErrorCode != "" {
+		parts = append(parts, e.ErrorCode)
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	return "riot: " + strings.Join(parts, ": ")
+}
+
+func (e *RiotError) Description() string {
+	if description, ok := riotErrorDescriptions[e.ErrorCode]; ok {
+		return tr(description)
+	}
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return tr("Your session has expired, please log in again")
+	case e.StatusCode == http.StatusForbidden:
+		return tr("Access was denied by Riot servers")
+	case e.StatusCode == http.StatusNotFound:
+		return tr("Riot servers could not find the requested data")
+	case e.StatusCode == http.StatusTooManyRequests:
+		return tr("Riot servers are rate limiting requests, please try again later")
+	case e.StatusCode >= 500:
+		return tr("Riot servers are currently unavailable")
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Error()
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding response from %s: %v", e.Host, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func parseRiotError(statusCode int, data []byte) *RiotError {
+	riotError := &RiotError{StatusCode: statusCode}
+	var body riotErrorBody
+	if json.Unmarshal(data, &body) != nil {
+		riotError.Message = strings.TrimSpace(string(data))
+		return riotError
+	}
+	riotError.ErrorCode = body.ErrorCode
+	if riotError.ErrorCode == "" {
+		riotError.ErrorCode = body.Error
+	}
+	riotError.Message = body.Message
+	if riotError.Message == "" {
+		riotError.Message = body.Status.Message
+	}
+	return riotError
+}
+
+func doRequest(ctx context.Context, req *http.Request, out any) error {
+	return sendRequest(ctx, req, out, req.Method == http.MethodGet || req.Method == http.MethodHead)
+}
+
+func doIdempotentRequest(ctx context.Context, req *http.Request, out any) error {
+	return sendRequest(ctx, req, out, true)
+}
+
+func sendRequest(ctx context.Context, req *http.Request, out any, idempotent bool) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = attemptRequest(ctx, req, out)
+		if err == nil || attempt+1 >= maxRequestAttempts || ctx.Err() != nil {
+			return err
+		}
+		var decodeError *DecodeError
+		if errors.As(err, &decodeError) {
+			return err
+		}
+		var riotError *RiotError
+		isRiotError := errors.As(err, &riotError)
+		if !idempotent {
+			return err
+		}
+		if isRiotError && riotError.StatusCode == http.StatusTooManyRequests {
+			if retryAfter > maxRetryAfter {
+				return err
+			}
+		} else if isRiotError && riotError.StatusCode < 500 {
+			return err
+		}
+		if retryAfter == 0 {
+			retryAfter = backoffDelay(attempt)
+		}
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func attemptRequest(ctx context.Context, req *http.Request, out any) (time.Duration, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	attemptReq := req.Clone(attemptCtx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return 0, err
+		}
+		attemptReq.Body = body
+	}
+	res, err := client.Do(attemptReq)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return parseRetryAfter(res.Header.Get("Retry-After")), parseRiotError(res.StatusCode, data)
+	}
+	if out == nil {
+		return 0, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return 0, &DecodeError{Host: req.URL.Host, Err: err}
+	}
+	return 0, nil
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		switch {
+		case seconds > maxRetryAfter.Seconds():
+			return maxRetryAfter + time.Second
+		case seconds > 0:
+			return time.Duration(seconds * float64(time.Second))
+		}
+		return 0
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+func backoffDelay(attempt int) time.Duration {
+	delay := minRetryDelay << attempt
+	if delay > maxRetryDelay || delay <= 0 {
+		delay = maxRetryDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func showError(message string, err error) {
+	var riotError *RiotError
+	if errors.As(err, &riotError) {
+		message += "\n\n" + riotError.Description()
+	} else if err != nil {
+		message += "\n\n" + err.Error()
+	}
+	displayError(message)
+}
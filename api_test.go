@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"2", 2 * time.Second},
+		{"0.5", 500 * time.Millisecond},
+		{"0", 0},
+		{"-3", 0},
+		{"soon", 0},
+		{"3600", maxRetryAfter + time.Second},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, test := range tests {
+		if got := parseRetryAfter(test.value); got != test.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", test.value, got, test.want)
+		}
+	}
+	date := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > 30*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, want up to 30s", date, got)
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{0, minRetryDelay},
+		{1, 2 * minRetryDelay},
+		{3, 8 * minRetryDelay},
+		{5, maxRetryDelay},
+		{70, maxRetryDelay},
+	}
+	for _, test := range tests {
+		for i := 0; i < 20; i++ {
+			if got := backoffDelay(test.attempt); got < test.max/2 || got > test.max {
+				t.Errorf("backoffDelay(%d) = %v, want between %v and %v", test.attempt, got, test.max/2, test.max)
+			}
+		}
+	}
+}
+
+func TestParseRiotError(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		body          string
+		wantErrorCode string
+		wantMessage   string
+	}{
+		{"errorCode", 400, `{"httpStatus":400,"errorCode":"BAD_CLAIMS","message":"Failure validating/decoding RSO Access Token"}`, "BAD_CLAIMS", "Failure validating/decoding RSO Access Token"},
+		{"error", 200, `{"type":"auth","error":"auth_failure","country":"fra"}`, "auth_failure", ""},
+		{"errorCode takes precedence over error", 400, `{"errorCode":"BAD_CLAIMS","error":"invalid_request"}`, "BAD_CLAIMS", ""},
+		{"status.message", 404, `{"status":{"message":"Data not found","status_code":404}}`, "", "Data not found"},
+		{"message takes precedence over status.message", 400, `{"message":"Bad request","status":{"message":"Ignored"}}`, "", "Bad request"},
+		{"non-JSON body", 502, "<html>Bad Gateway</html>\n", "", "<html>Bad Gateway</html>"},
+		{"JSON string body", 500, `"Internal Server Error"`, "", `"Internal Server Error"`},
+		{"empty body", 503, "", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseRiotError(test.statusCode, []byte(test.body))
+			if got.StatusCode != test.statusCode || got.ErrorCode != test.wantErrorCode || got.Message != test.wantMessage {
+				t.Errorf("parseRiotError() = %+v, want %d %q %q", got, test.statusCode, test.wantErrorCode, test.wantMessage)
+			}
+		})
+	}
+}
+
+func TestRiotErrorDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		err  RiotError
+		want string
+	}{
+		{"known error code", RiotError{StatusCode: 200, ErrorCode: "auth_failure"}, "Your username or password is incorrect"},
+		{"error code takes precedence over status", RiotError{StatusCode: 400, ErrorCode: "BAD_CLAIMS"}, "Your session has expired, please log in again"},
+		{"unauthorized", RiotError{StatusCode: 401, ErrorCode: "UNKNOWN"}, "Your session has expired, please log in again"},
+		{"forbidden", RiotError{StatusCode: 403}, "Access was denied by Riot servers"},
+		{"not found", RiotError{StatusCode: 404, Message: "Data not found"}, "Riot servers could not find the requested data"},
+		{"rate limited", RiotError{StatusCode: 429}, "Riot servers are rate limiting requests, please try again later"},
+		{"server error", RiotError{StatusCode: 503}, "Riot servers are currently unavailable"},
+		{"message", RiotError{StatusCode: 400, Message: "Bad request"}, "Bad request"},
+		{"nothing to describe", RiotError{StatusCode: 400, ErrorCode: "UNKNOWN"}, "riot: HTTP 400: UNKNOWN"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.err.Description(); got != test.want {
+				t.Errorf("Description() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSendRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []string
+		retryAfter   string
+		idempotent   bool
+		wantAttempts int32
+		wantErr      func(error) bool
+	}{
+		{"success", []string{"200 {}"}, "", true, 1, nil},
+		{"decode error is not retried", []string{"200 <html>", "200 {}"}, "", true, 1, isDecodeError},
+		{"server error is retried", []string{"503 {}", "200 {}"}, "0.01", true, 2, nil},
+		{"server error is not retried for non-idempotent requests", []string{"503 {}", "200 {}"}, "0.01", false, 1, hasStatus(503)},
+		{"client error is not retried", []string{"404 {}", "200 {}"}, "0.01", true, 1, hasStatus(404)},
+		{"rate limit is retried", []string{"429 {}", "200 {}"}, "0.01", true, 2, nil},
+		{"rate limit is not retried for non-idempotent requests", []string{"429 {}", "200 {}"}, "0.01", false, 1, hasStatus(429)},
+		{"long rate limit is not retried", []string{"429 {}", "200 {}"}, "3600", true, 1, hasStatus(429)},
+		{"attempts are limited", []string{"500 {}", "500 {}", "500 {}", "500 {}", "200 {}"}, "0.01", true, maxRequestAttempts, hasStatus(500)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := test.responses[atomic.AddInt32(&attempts, 1)-1]
+				status, body, _ := strings.Cut(response, " ")
+				code, _ := strconv.Atoi(status)
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
+				w.WriteHeader(code)
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+			previous := client
+			client = server.Client()
+			defer func() { client = previous }()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out map[string]any
+			err = sendRequest(context.Background(), req, &out, test.idempotent)
+			if test.wantErr == nil && err != nil || test.wantErr != nil && !test.wantErr(err) {
+				t.Errorf("sendRequest() error = %v", err)
+			}
+			if attempts != test.wantAttempts {
+				t.Errorf("sendRequest() made %d attempts, want %d", attempts, test.wantAttempts)
+			}
+		})
+	}
+}
+
+func isDecodeError(err error) bool {
+	var decodeError *DecodeError
+	return errors.As(err, &decodeError)
+}
+
+func hasStatus(statusCode int) func(error) bool {
+	return func(err error) bool {
+		var riotError *RiotError
+		return errors.As(err, &riotError) && riotError.StatusCode == statusCode
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func stubClient(t *testing.T, fn roundTripFunc) {
+	previous := client
+	client = &http.Client{Transport: fn}
+	t.Cleanup(func() { client = previous })
+}
+
+func stubResponse(statusCode int, body string) *http.Response {
+	return &http.Response{StatusCode: statusCode, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+}
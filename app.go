@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+const scheduledRefreshTimeout = 10 * time.Minute
+
+var globalStore = GlobalStore{}
+var locale string
+var appContext, cancelAppContext = context.WithCancel(context.Background())
+
+type appOptions struct {
+	recordDir   string
+	replayDir   string
+	portable    bool
+	searchQuery string
+}
+
+func parseAppOptions() appOptions {
+	var options appOptions
+	flag.StringVar(&options.recordDir, "record", "", "record every Riot and content API exchange to fixture files in this directory")
+	flag.StringVar(&options.replayDir, "replay", "", "serve Riot and content API responses from fixture files in this directory")
+	flag.BoolVar(&options.portable, "portable", false, "keep settings and saved skins in a saves directory next to the executable")
+	flag.StringVar(&options.searchQuery, "search", "", "print the skins matching this search and exit")
+	flag.Parse()
+	return options
+}
+
+func setupApp(options appOptions) []error {
+	var err error
+	var startupErrors []error
+	if err = setupStateDir(options.portable); err != nil {
+		stateDir = portableDirName
+		startupErrors = append(startupErrors, fmt.Errorf("data directory: %w", err))
+	}
+	if globalStore.Settings, err = loadSettings(); err != nil {
+		startupErrors = append(startupErrors, fmt.Errorf("%s: %w", statePath(settingsFile), err))
+	}
+	transportErr := setupTransport(globalStore.Settings.Network, options.recordDir, options.replayDir)
+	if transportErr != nil {
+		if options.replayDir != "" {
+			log.Fatal(transportErr)
+		}
+		startupErrors = append(startupErrors, fmt.Errorf("network settings: %w", transportErr))
+	}
+	if err = setupNotifierTransport(globalStore.Settings.Network); err != nil && transportErr == nil {
+		startupErrors = append(startupErrors, fmt.Errorf("network settings: %w", err))
+	}
+	setupLanguage(globalStore.Settings.Language)
+	if globalStore.NotificationHistory, err = loadNotificationHistory(statePath(notificationHistoryFile)); err != nil {
+		startupErrors = append(startupErrors, fmt.Errorf("%s: %w", statePath(notificationHistoryFile), err))
+	}
+	if globalStore.Snoozes, err = loadSnoozes(statePath(snoozesFile)); err != nil {
+		startupErrors = append(startupErrors, fmt.Errorf("%s: %w", statePath(snoozesFile), err))
+	}
+	if globalStore.Outbox, err = loadOutbox(statePath(outboxFile)); err != nil {
+		startupErrors = append(startupErrors, fmt.Errorf("%s: %w", statePath(outboxFile), err))
+	}
+	globalStore.DeliveryLog = newDeliveryLog(statePath(deliveryLogFile))
+	if err = globalStore.Settings.Notifications.QuietHours.Validate(); err != nil {
+		startupErrors = append(startupErrors, fmt.Errorf("quiet hours: %w", err))
+	}
+	return startupErrors
+}
+
+func runSearchCommand(ctx context.Context, query string) {
+	skins, err := fetchSkins(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	attributes, _ := fetchSkinAttributes(ctx)
+	for _, result := range newSkinSearchIndex(skins, attributes).Search(query, 20) {
+		fmt.Printf("%.2f\t%s\t%s\n", result.Score, result.Skin.Id, localizedName(result.Skin, locale))
+	}
+}
+
+func notifyUserIfTheyHaveWantedSkins() {
+	matches, errs := findMatches(globalStore.Ui.selectedSkinsListBox.AllSkins, globalStore.Rules, globalStore.CurrentOffers)
+	if len(errs) > 0 {
+		showError(tr("%d watch rules could not be evaluated", len(errs)), errs[0])
+	}
+	var notifyErrs []error
+	for _, match := range matches {
+		notifyErrs = append(notifyErrs, deliverNotification(appContext, newMatchNotification(match))...)
+	}
+	if len(globalStore.CurrentOffers) > 0 {
+		snapshot := newShopSnapshot(globalStore.CurrentOffers, matches)
+		notifyErrs = append(notifyErrs, sendShopSnapshot(appContext, snapshot)...)
+		notifyErrs = append(notifyErrs, sendRotationSummary(snapshot)...)
+	}
+	if len(notifyErrs) > 0 {
+		showError(tr("Some notifications could not be sent"), notifyErrs[0])
+	}
+}
+
+func startCron() {
+	c := cron.New()
+	c.AddFunc("0 0 2 ? * *", func() {
+		ctx, cancel := context.WithTimeout(appContext, scheduledRefreshTimeout)
+		defer cancel()
+		seedUser(ctx)
+	})
+	c.Start()
+}
+
+func setupChannels() {
+	globalStore.Channels.MFAToken = make(chan bool, 1)
+	globalStore.Channels.LoginWindow = make(chan bool, 1)
+}
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/url"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/lxn/walk"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 )
 
 type User struct {
@@ -59,13 +62,15 @@ type ParsedURL struct {
 
 type AccessTokenContainer struct {
 	Type     string `json:"type"`
+	Error    string `json:"error"`
 	Response struct {
 		Mode       string `json:"mode"`
 		Parameters struct {
 			Uri ParsedURL `json:"uri"`
 		} `json:"parameters"`
 	} `json:"response"`
-	Country string `json:"country"`
+	Multifactor Multifactor `json:"multifactor"`
+	Country     string      `json:"country"`
 }
 
 type EntitlementResponse struct {
@@ -76,11 +81,38 @@ type UserId struct {
 	Sub string `json:"sub"`
 }
 
+type StoreOffer struct {
+	OfferID string         `json:"OfferID"`
+	Cost    map[string]int `json:"Cost"`
+}
+
+type BonusStoreOffer struct {
+	BonusOfferID    string         `json:"BonusOfferID"`
+	Offer           StoreOffer     `json:"Offer"`
+	DiscountPercent int            `json:"DiscountPercent"`
+	DiscountCosts   map[string]int `json:"DiscountCosts"`
+}
+
+type StoreBundle struct {
+	DataAssetID                string         `json:"DataAssetID"`
+	TotalBaseCost              map[string]int `json:"TotalBaseCost"`
+	TotalDiscountedCost        map[string]int `json:"TotalDiscountedCost"`
+	DurationRemainingInSeconds int            `json:"DurationRemainingInSeconds"`
+}
+
 type Shop struct {
+	FeaturedBundle struct {
+		Bundles []StoreBundle `json:"Bundles"`
+	} `json:"FeaturedBundle"`
 	SkinsPanelLayout struct {
-		SingleItemOffers                           []string `json:"SingleItemOffers"`
-		SingleItemOffersRemainingDurationInSeconds int      `json:"SingleItemOffersRemainingDurationInSeconds"`
+		SingleItemOffers                           []string     `json:"SingleItemOffers"`
+		SingleItemStoreOffers                      []StoreOffer `json:"SingleItemStoreOffers"`
+		SingleItemOffersRemainingDurationInSeconds int          `json:"SingleItemOffersRemainingDurationInSeconds"`
 	} `json:"SkinsPanelLayout"`
+	BonusStore struct {
+		BonusStoreOffers                     []BonusStoreOffer `json:"BonusStoreOffers"`
+		BonusStoreRemainingDurationInSeconds int               `json:"BonusStoreRemainingDurationInSeconds"`
+	} `json:"BonusStore"`
 }
 
 type SkinDataResponse struct {
@@ -91,25 +123,35 @@ type SkinDataResponse struct {
 	} `json:"data"`
 }
 
-type SkinLayout struct {
-	LinkLabel *walk.LinkLabel
+type BundleDataResponse struct {
+	Data struct {
+		Uuid        string `json:"uuid"`
+		DisplayName string `json:"displayName"`
+	} `json:"data"`
 }
 
-type UiElems struct {
-	skinsListBox         MultiSelectList
-	selectedSkinsListBox MultiSelectList
-	shop                 *walk.Composite
-	mainWindow           *walk.MainWindow
-	skinLayouts          []SkinLayout
-	notifyIcon           *walk.NotifyIcon
+type Bundle struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Price     int       `json:"price"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 type GlobalStore struct {
-	Ui          UiElems
-	CurrentShop []Skin
-	User        User
-	Channels    struct {
-		NewToken    chan string
+	Ui                  UiElems
+	CurrentShop         []Skin
+	CurrentOffers       []Offer
+	CurrentBundles      []Bundle
+	Rules               []WatchRule
+	SearchIndex         *SkinSearchIndex
+	Notifiers           []Notifier
+	NotificationHistory *NotificationHistory
+	Outbox              *Outbox
+	DeliveryLog         *DeliveryLog
+	Snoozes             *Snoozes
+	User                User
+	Settings            Settings
+	Channels            struct {
 		LoginWindow chan bool
 		MFAToken    chan bool
 	}
@@ -126,6 +168,18 @@ type Skin struct {
 	AssetName      string
 	AssetPath      string
 	Video          string
+	Chroma         string
+	Priority       Priority
+	MaxPrice       int
+	Price          int
+	resolvedName   *resolvedName
+}
+
+type resolvedName struct {
+	locale   string
+	fallback string
+	name     string
+	key      []byte
 }
 
 type SortedSkins []Skin
@@ -133,28 +187,117 @@ type SynchronizedMap struct {
 	*sync.Map
 }
 
-type SkinNameVideo struct {
-	Name  string
-	Video string
+type collatedSkins struct {
+	skins []Skin
+	keys  [][]byte
 }
 
-func (s SortedSkins) Len() int {
-	return len(s)
+func (c collatedSkins) Len() int {
+	return len(c.skins)
 }
 
-func (s SortedSkins) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+func (c collatedSkins) Swap(i, j int) {
+	c.skins[i], c.skins[j] = c.skins[j], c.skins[i]
+	c.keys[i], c.keys[j] = c.keys[j], c.keys[i]
 }
 
-func (s SortedSkins) Less(i, j int) bool {
-	if _, ok := s[i].LocalizedNames.Load(locale); !ok {
-		locale = "en-US"
+func (c collatedSkins) Less(i, j int) bool {
+	if order := bytes.Compare(c.keys[i], c.keys[j]); order != 0 {
+		return order < 0
+	}
+	return c.skins[i].Id < c.skins[j].Id
+}
+
+func newCollator(locale string) *collate.Collator {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.AmericanEnglish
 	}
-	res1, _ := s[i].LocalizedNames.Load(locale)
-	res2, _ := s[j].LocalizedNames.Load(locale)
-	localizedSlice := []string{res1.(string), res2.(string)}
-	sort.Strings(localizedSlice)
-	return localizedSlice[0] == res1
+	return collate.New(tag, collate.Numeric)
+}
+
+func (s SortedSkins) Sort(locale string) {
+	fallback := globalStore.Settings.FallbackLanguage
+	var collator *collate.Collator
+	var buffer collate.Buffer
+	var names []resolvedName
+	keys := make([][]byte, len(s))
+	for i := range s {
+		resolved := s[i].resolvedName
+		if resolved == nil || resolved.locale != locale || resolved.fallback != fallback {
+			if collator == nil {
+				collator = newCollator(locale)
+				names = make([]resolvedName, len(s))
+			}
+			name := resolveLocalizedName(s[i].LocalizedNames, s[i].Name, locale, fallback)
+			names[i] = resolvedName{locale: locale, fallback: fallback, name: name, key: collator.KeyFromString(&buffer, name)}
+			resolved = &names[i]
+			s[i].resolvedName = resolved
+		}
+		keys[i] = resolved.key
+	}
+	sort.Sort(collatedSkins{skins: s, keys: keys})
+}
+
+func localizedName(skin Skin, locale string) string {
+	fallback := globalStore.Settings.FallbackLanguage
+	if resolved := skin.resolvedName; resolved != nil && resolved.locale == locale && resolved.fallback == fallback {
+		return resolved.name
+	}
+	return resolveLocalizedName(skin.LocalizedNames, skin.Name, locale, fallback)
+}
+
+func resolveLocalizedName(names SynchronizedMap, defaultName string, locale string, fallback string) string {
+	if names.Map != nil {
+		if name, ok := names.Load(locale); ok && name != "" {
+			return name.(string)
+		}
+	}
+	available := make(map[string]string)
+	if names.Map != nil {
+		names.Range(func(key any, value any) bool {
+			if name, ok := value.(string); ok && name != "" {
+				available[key.(string)] = name
+			}
+			return true
+		})
+	}
+	if name, ok := available[locale]; ok {
+		return name
+	}
+	locales := make([]string, 0, len(available))
+	for key := range available {
+		locales = append(locales, key)
+	}
+	sort.Strings(locales)
+	if base := localeBase(locale); base != "" {
+		for _, key := range locales {
+			if localeBase(key) == base {
+				return available[key]
+			}
+		}
+	}
+	for _, candidate := range []string{fallback, "en-US"} {
+		if name, ok := available[candidate]; ok && candidate != "" {
+			return name
+		}
+	}
+	if defaultName != "" {
+		return defaultName
+	}
+	if len(locales) > 0 {
+		return available[locales[0]]
+	}
+	return ""
+}
+
+func localeBase(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return ""
+	}
+	base, _ := tag.Base()
+	return base.String()
 }
 
 func setRequestHeaders(req *http.Request) *http.Request {
@@ -193,6 +336,9 @@ func (syncMap *SynchronizedMap) UnmarshalJSON(data []byte) error {
 
 func (syncMap *SynchronizedMap) MarshalJSON() ([]byte, error) {
 	dataMap := make(map[string]string)
+	if syncMap.Map == nil {
+		return json.Marshal(dataMap)
+	}
 	syncMap.Range(func(key any, value any) bool {
 		dataMap[key.(string)] = value.(string)
 		return true
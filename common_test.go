@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"golang.org/x/text/collate"
+)
+
+var benchmarkWeapons = []string{"Classic", "Shorty", "Frenzy", "Ghost", "Sheriff", "Stinger", "Spectre", "Bucky", "Judge", "Bulldog", "Guardian", "Phantom", "Vandal", "Marshal", "Outlaw", "Operator", "Ares", "Odin", "Couteau"}
+
+var benchmarkCollections = []string{"Prime", "Élan", "Reaver", "Oni", "Ion", "Glitchpop", "Singularity", "Øutlaw", "Sentinels of Light", "RGX 11z Pro", "Araxys", "Champions 2021", "Champions 2022", "Kuronami", "Ruination", "Forsaken", "Magepunk", "Nunca Olvidados", "Sovereign", "Spline", "Protocol 781-A", "Neo Frontier", "Gaia's Vengeance", "Zedd", "Ego", "Xenohunter", "Titanmail", "Smite", "Radiant Entertainment System", "Minima", "Cryostasis", "BlastX", "Origin", "Wasteland", "Prelude to Chaos", "Tethered Realms", "Gravitational Uranium Neuroblaster", "Luxe", "Infantry", "Winterwunderland", "Coalition: Cobra", "Undercity", "Altitude", "Immortalized", "Soulstrife", "Abyssal", "Valiant Hero", "Endeavour", "Rune Stone", "Sakura", "Gaia", "Silvanus", "Hivemind", "Orion", "Genesis", "Mystbloom", "Overdrive", "Imperium", "Sarmad", "Cryptid", "Daydreams", "Primordium", "Intergrade", "Evori Dreamwings", "Doodle Buds", "Team Ace", "Sensation", "Snowfall", "Polyfox", "Oni 2.0"}
+
+func benchmarkCatalog() SortedSkins {
+	random := rand.New(rand.NewSource(1))
+	var skins SortedSkins
+	for _, collection := range benchmarkCollections {
+		for _, weapon := range benchmarkWeapons {
+			english := collection + " " + weapon
+			names := SynchronizedMap{&sync.Map{}}
+			names.Store("en-US", english)
+			names.Store("fr-FR", weapon+" "+collection)
+			skins = append(skins, Skin{Id: fmt.Sprintf("%08x", random.Uint32()), Name: english, LocalizedNames: names})
+		}
+	}
+	random.Shuffle(len(skins), func(i, j int) { skins[i], skins[j] = skins[j], skins[i] })
+	return skins
+}
+
+type legacySortedSkins struct {
+	skins  SortedSkins
+	locale string
+}
+
+func (s legacySortedSkins) Len() int {
+	return len(s.skins)
+}
+
+func (s legacySortedSkins) Swap(i, j int) {
+	s.skins[i], s.skins[j] = s.skins[j], s.skins[i]
+}
+
+func (s legacySortedSkins) Less(i, j int) bool {
+	res1, _ := s.skins[i].LocalizedNames.Load(s.locale)
+	res2, _ := s.skins[j].LocalizedNames.Load(s.locale)
+	localizedSlice := []string{res1.(string), res2.(string)}
+	sort.Strings(localizedSlice)
+	return localizedSlice[0] == res1
+}
+
+type comparedSkins struct {
+	skins    SortedSkins
+	locale   string
+	collator *collate.Collator
+}
+
+func (s comparedSkins) Len() int {
+	return len(s.skins)
+}
+
+func (s comparedSkins) Swap(i, j int) {
+	s.skins[i], s.skins[j] = s.skins[j], s.skins[i]
+}
+
+func (s comparedSkins) Less(i, j int) bool {
+	return s.collator.CompareString(localizedName(s.skins[i], s.locale), localizedName(s.skins[j], s.locale)) < 0
+}
+
+func BenchmarkSort(b *testing.B) {
+	catalog := benchmarkCatalog()
+	resolved := make(SortedSkins, len(catalog))
+	copy(resolved, catalog)
+	resolved.Sort("fr-FR")
+	rand.New(rand.NewSource(1)).Shuffle(len(resolved), func(i, j int) { resolved[i], resolved[j] = resolved[j], resolved[i] })
+	benchmarks := []struct {
+		name    string
+		catalog SortedSkins
+		sort    func(skins SortedSkins)
+	}{
+		{"legacy", catalog, func(skins SortedSkins) { sort.Sort(legacySortedSkins{skins: skins, locale: "fr-FR"}) }},
+		{"compare", catalog, func(skins SortedSkins) {
+			sort.Sort(comparedSkins{skins: skins, locale: "fr-FR", collator: newCollator("fr-FR")})
+		}},
+		{"keys", catalog, func(skins SortedSkins) { skins.Sort("fr-FR") }},
+		{"resolved keys", resolved, func(skins SortedSkins) { skins.Sort("fr-FR") }},
+	}
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.name, func(b *testing.B) {
+			skins := make(SortedSkins, len(benchmark.catalog))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(skins, benchmark.catalog)
+				benchmark.sort(skins)
+			}
+		})
+	}
+}
+
+func TestSortedSkinsSort(t *testing.T) {
+	skin := func(id string, names ...string) Skin {
+		localized := SynchronizedMap{&sync.Map{}}
+		for i := 0; i+1 < len(names); i += 2 {
+			localized.Store(names[i], names[i+1])
+		}
+		return Skin{Id: id, LocalizedNames: localized}
+	}
+	tests := []struct {
+		locale string
+		skins  SortedSkins
+		want   []string
+	}{
+		{"en-US", SortedSkins{skin("1", "en-US", "Champions 2022"), skin("2", "en-US", "Champions 2021"), skin("3", "en-US", "Champions 10")}, []string{"3", "2", "1"}},
+		{"fr-FR", SortedSkins{skin("1", "fr-FR", "Vandale"), skin("2", "fr-FR", "Élan"), skin("3", "fr-FR", "elite")}, []string{"2", "3", "1"}},
+		{"fr-FR", SortedSkins{skin("1", "en-US", "Zedd"), skin("2", "fr-FR", "Ares"), skin("3", "en-US", "Bucky")}, []string{"2", "3", "1"}},
+		{"en-US", SortedSkins{skin("b", "en-US", "Prime"), skin("a", "en-US", "prime")}, []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		test.skins.Sort(test.locale)
+		for i, id := range test.want {
+			if test.skins[i].Id != id {
+				var got []string
+				for _, skin := range test.skins {
+					got = append(got, skin.Id)
+				}
+				t.Errorf("Sort(%s) = %v, want %v", test.locale, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLocalizedNameFollowsTheSortLocale(t *testing.T) {
+	previous := globalStore.Settings
+	t.Cleanup(func() { globalStore.Settings = previous })
+	names := SynchronizedMap{&sync.Map{}}
+	names.Store("en-US", "Prime Vandal")
+	names.Store("fr-FR", "Vandal Prime")
+	skins := SortedSkins{{Id: "1", LocalizedNames: names}}
+	tests := []struct {
+		sortLocale string
+		fallback   string
+		locale     string
+		want       string
+	}{
+		{"fr-FR", "", "fr-FR", "Vandal Prime"},
+		{"fr-FR", "", "en-US", "Prime Vandal"},
+		{"fr-FR", "", "de-DE", "Prime Vandal"},
+		{"fr-FR", "fr-FR", "de-DE", "Vandal Prime"},
+		{"en-US", "", "en-US", "Prime Vandal"},
+	}
+	for _, test := range tests {
+		globalStore.Settings.FallbackLanguage = test.fallback
+		skins.Sort(test.sortLocale)
+		if got := localizedName(skins[0], test.locale); got != test.want {
+			t.Errorf("localizedName(%s) after Sort(%s) = %q, want %q", test.locale, test.sortLocale, got, test.want)
+		}
+	}
+}
+
+func TestResolveLocalizedName(t *testing.T) {
+	names := SynchronizedMap{&sync.Map{}}
+	names.Store("en-US", "Prime Vandal")
+	names.Store("es-ES", "Vandal Prime (ES)")
+	names.Store("fr-FR", "Vandal Prime")
+	names.Store("pt-BR", "")
+	tests := []struct {
+		name        string
+		names       SynchronizedMap
+		defaultName string
+		locale      string
+		fallback    string
+		want        string
+	}{
+		{"exact locale", names, "Default", "fr-FR", "", "Vandal Prime"},
+		{"regional variant", names, "Default", "es-MX", "", "Vandal Prime (ES)"},
+		{"fallback language", names, "Default", "de-DE", "fr-FR", "Vandal Prime"},
+		{"english", names, "Default", "de-DE", "", "Prime Vandal"},
+		{"empty names are ignored", names, "Default", "pt-BR", "", "Prime Vandal"},
+		{"default name", SynchronizedMap{&sync.Map{}}, "Default", "fr-FR", "", "Default"},
+		{"nil map", SynchronizedMap{}, "Default", "fr-FR", "", "Default"},
+		{"any name", names, "", "de-DE", "ja-JP", "Prime Vandal"},
+	}
+	for _, test := range tests {
+		if got := resolveLocalizedName(test.names, test.defaultName, test.locale, test.fallback); got != test.want {
+			t.Errorf("%s: resolveLocalizedName() = %q, want %q", test.name, got, test.want)
+		}
+	}
+	only := SynchronizedMap{&sync.Map{}}
+	only.Store("ko-KR", "프라임 밴달")
+	if got := resolveLocalizedName(only, "", "de-DE", ""); got != "프라임 밴달" {
+		t.Errorf("resolveLocalizedName() = %q, want the only name", got)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/danieljoos/wincred"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func loadSavedUser() (User, error) {
+	var user User
+	cred, err := wincred.GetGenericCredential("ValorantShopwatcher")
+	if err == nil {
+		decoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+		blob, _ := decoder.Bytes(cred.CredentialBlob)
+		if err != nil {
+			log.Fatal(err)
+		}
+		s := strings.Split(string(blob), "\x00")
+		user = User{cred.UserName, s[0], s[1], s[2]}
+	}
+	return user, err
+}
+
+func saveUserData(user User) {
+	cred := wincred.NewGenericCredential("ValorantShopwatcher")
+	cred.Persist = wincred.PersistEnterprise
+	cred.TargetAlias = "ValorantShopwatcher"
+	cred.TargetName = "ValorantShopwatcher"
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	blob, _ := encoder.Bytes([]byte(user.Password + "\x00" + user.Region + "\x00" + user.AccessToken))
+	cred.CredentialBlob = blob
+	cred.UserName = user.Login
+	err := cred.Write()
+	if err != nil {
+		showError(tr("The app could not save your credentials"), err)
+	}
+}
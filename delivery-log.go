@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	deliveryLogFile    = "deliveries.log"
+	maxDeliveryLogSize = 1 << 20
+	deliverySent       = "sent"
+	deliveryFailed     = "failed"
+	deliveryDropped    = "dropped"
+)
+
+type DeliveryLogEntry struct {
+	Time    time.Time `json:"time"`
+	Channel string    `json:"channel"`
+	Account string    `json:"account,omitempty"`
+	Skin    string    `json:"skin"`
+	Status  string    `json:"status"`
+	Attempt int       `json:"attempt"`
+	Error   string    `json:"error,omitempty"`
+	Output  string    `json:"output,omitempty"`
+}
+
+type DeliveryLog struct {
+	mutex sync.Mutex
+	path  string
+}
+
+func newDeliveryLog(path string) *DeliveryLog {
+	return &DeliveryLog{path: path}
+}
+
+func (deliveryLog *DeliveryLog) Append(entry OutboxEntry, status string, deliveryErr error, output string) error {
+	logEntry := DeliveryLogEntry{
+		Time:    time.Now(),
+		Channel: entry.Channel,
+		Account: entry.Notification.Account,
+		Skin:    localizedName(entry.Notification.Match.Skin, locale),
+		Status:  status,
+		Attempt: entry.Attempts,
+		Output:  output,
+	}
+	if entry.Notification.Summary != nil {
+		logEntry.Skin = entry.Notification.Title
+	}
+	if deliveryErr != nil {
+		logEntry.Error = deliveryErr.Error()
+	}
+	return deliveryLog.write(logEntry)
+}
+
+func (deliveryLog *DeliveryLog) write(logEntry DeliveryLogEntry) error {
+	data, err := json.Marshal(logEntry)
+	if err != nil {
+		return err
+	}
+	deliveryLog.mutex.Lock()
+	defer deliveryLog.mutex.Unlock()
+	if info, err := os.Stat(deliveryLog.path); err == nil && info.Size() > maxDeliveryLogSize {
+		os.Rename(deliveryLog.path, deliveryLog.path+".1")
+	}
+	file, err := os.OpenFile(deliveryLog.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (deliveryLog *DeliveryLog) Recent(limit int) ([]DeliveryLogEntry, error) {
+	deliveryLog.mutex.Lock()
+	defer deliveryLog.mutex.Unlock()
+	file, err := os.Open(deliveryLog.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []DeliveryLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var logEntry DeliveryLogEntry
+		if json.Unmarshal(scanner.Bytes(), &logEntry) == nil {
+			entries = append(entries, logEntry)
+		}
+	}
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, scanner.Err()
+}
+
+func (logEntry DeliveryLogEntry) String() string {
+	parts := []string{logEntry.Time.Format("2006-01-02 15:04"), logEntry.Status, logEntry.Channel, logEntry.Skin}
+	if logEntry.Account != "" {
+		parts = append(parts, logEntry.Account)
+	}
+	line := strings.Join(parts, "  ")
+	if logEntry.Error != "" {
+		line += "  (" + logEntry.Error + ")"
+	}
+	if logEntry.Output != "" {
+		line += "  > " + strings.Join(strings.Fields(logEntry.Output), " ")
+	}
+	return line
+}
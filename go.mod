@@ -1,6 +1,6 @@
 module github.com/Loadeksdi/shopwatcher
 
-go 1.18
+go 1.20
 
 require (
 	github.com/akavel/rsrc v0.10.2 // indirect
@@ -17,6 +17,7 @@ require (
 	golang.org/x/net v0.0.0-20220425223048-2871e0cb64e4 // indirect
 	golang.org/x/sync v0.0.0-20220513210516-0976fa681c29 // indirect
 	golang.org/x/sys v0.0.0-20220503163025-988cb79eb6c6 // indirect
+	golang.org/x/term v0.1.0 // indirect
 	golang.org/x/text v0.3.7 // indirect
 	gopkg.in/Knetic/govaluate.v3 v3.0.0 // indirect
 )
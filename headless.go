@@ -0,0 +1,150 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+const savedUserFile = "user.json"
+
+type skinList struct {
+	AllSkins []Skin
+}
+
+type UiElems struct {
+	skinsListBox         skinList
+	selectedSkinsListBox skinList
+}
+
+var terminal = bufio.NewReader(os.Stdin)
+
+func (list *skinList) FeedList(skins SortedSkins) {
+	skins.Sort(locale)
+	list.AllSkins = skins
+}
+
+func displayError(message string) {
+	log.Println(message)
+}
+
+func promptLine(label string) string {
+	fmt.Print(label + " ")
+	line, _ := terminal.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func promptPassword(label string) string {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return promptLine(label)
+	}
+	fmt.Print(label + " ")
+	password, _ := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	return string(password)
+}
+
+func promptLogin() {
+	user := globalStore.User
+	if user.Login == "" {
+		user.Login = os.Getenv("SHOPWATCHER_USERNAME")
+	}
+	if user.Password == "" {
+		user.Password = os.Getenv("SHOPWATCHER_PASSWORD")
+	}
+	if user.Region == "" {
+		user.Region = os.Getenv("SHOPWATCHER_REGION")
+	}
+	if user.Login == "" {
+		user.Login = promptLine(tr("Username:"))
+	}
+	if user.Password == "" {
+		user.Password = promptPassword(tr("Password:"))
+	}
+	if user.Region == "" {
+		user.Region = promptLine(tr("Region:"))
+	}
+	user.Region = strings.ToUpper(user.Region)
+	globalStore.User = user
+	saveUserData(user)
+	globalStore.Channels.LoginWindow <- true
+}
+
+func promptMfaCode(ctx context.Context, codeLength int) error {
+	code := promptLine(tr("Please enter MFA code given by Riot Games"))
+	if err := submitMfaCode(ctx, code); err != nil {
+		return fmt.Errorf("%s: %w", tr("Couldn't connect with MFA"), err)
+	}
+	return nil
+}
+
+func loadSavedUser() (User, error) {
+	var user User
+	data, err := os.ReadFile(statePath(savedUserFile))
+	if err != nil {
+		return user, err
+	}
+	err = json.Unmarshal(data, &user)
+	return user, err
+}
+
+func saveUserData(user User) {
+	user.Password = ""
+	data, err := json.Marshal(user)
+	if err == nil {
+		err = writeFileAtomic(statePath(savedUserFile), data)
+	}
+	if err != nil {
+		showError(tr("The app could not save your credentials"), err)
+	}
+}
+
+func drawShop() {
+	fmt.Println(tr("My current shop"))
+	for _, skin := range globalStore.CurrentShop {
+		fmt.Println("  " + localizedName(skin, locale) + "  " + skin.Video)
+	}
+	notifyUserIfTheyHaveWantedSkins()
+}
+
+func main() {
+	options := parseAppOptions()
+	startupErrors := setupApp(options)
+	if options.searchQuery != "" {
+		runSearchCommand(appContext, options.searchQuery)
+		return
+	}
+	setupChannels()
+	var err error
+	if globalStore.User, err = loadSavedUser(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		startupErrors = append(startupErrors, fmt.Errorf("%s: %w", statePath(savedUserFile), err))
+	}
+	loadSavedSkins()
+	startupErrors = append(startupErrors, setupNotifiers()...)
+	for _, err := range startupErrors {
+		showError(tr("The app could not apply your settings, defaults are used instead"), err)
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancelAppContext()
+	}()
+	feedData(appContext)
+	go startCron()
+	go runOutbox(appContext)
+	go seedUser(appContext)
+	<-appContext.Done()
+}
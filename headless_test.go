@@ -0,0 +1,78 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveUserDataKeepsThePasswordOut(t *testing.T) {
+	previous := stateDir
+	stateDir = t.TempDir()
+	t.Cleanup(func() { stateDir = previous })
+	saveUserData(User{Login: "player", Password: "hunter2", Region: "EU", AccessToken: "token"})
+	data, err := os.ReadFile(statePath(savedUserFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("%s contains the password: %s", savedUserFile, data)
+	}
+	user, err := loadSavedUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != (User{Login: "player", Region: "EU", AccessToken: "token"}) {
+		t.Errorf("loadSavedUser() = %+v", user)
+	}
+}
+
+func TestSeedUserReplay(t *testing.T) {
+	tests := []struct {
+		fixtures   string
+		input      string
+		wantOffers []string
+		wantError  string
+	}{
+		{"login", "", []string{"Prime Vandal", "Reaver Sheriff"}, ""},
+		{"multifactor", "123456\n", []string{"Prime Vandal", "Reaver Sheriff"}, ""},
+		{"auth-failure", "", nil, tr("The app could not log you in")},
+	}
+	for _, test := range tests {
+		t.Run(test.fixtures, func(t *testing.T) {
+			useTestOutbox(t)
+			previousStateDir, previousTransport, previousTerminal := stateDir, client.Transport, terminal
+			t.Cleanup(func() {
+				stateDir, client.Transport, terminal = previousStateDir, previousTransport, previousTerminal
+				skinAttributesCache.byId = nil
+			})
+			stateDir = t.TempDir()
+			terminal = bufio.NewReader(strings.NewReader(test.input))
+			skinAttributesCache.byId = nil
+			if err := setupTransport(NetworkSettings{}, "", filepath.Join("testdata", "replay", test.fixtures)); err != nil {
+				t.Fatal(err)
+			}
+			globalStore.User = User{Login: "player", Password: "hunter2", Region: "EU", AccessToken: "expired"}
+			output := captureErrors(t)
+			seedUser(context.Background())
+			var names []string
+			for _, offer := range globalStore.CurrentOffers {
+				names = append(names, offer.Skin.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(test.wantOffers, ",") {
+				t.Errorf("offers = %v, want %v", names, test.wantOffers)
+			}
+			if test.wantError == "" && output.Len() > 0 || !strings.Contains(output.String(), test.wantError) {
+				t.Errorf("logged %q, want %q", output.String(), test.wantError)
+			}
+			if test.wantOffers != nil && globalStore.CurrentOffers[0].Attributes.Weapon != "Vandal" {
+				t.Errorf("attributes = %+v", globalStore.CurrentOffers[0].Attributes)
+			}
+		})
+	}
+}
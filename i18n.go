@@ -0,0 +1,149 @@
+package main
+
+import (
+	lang "github.com/cloudfoundry-attic/jibber_jabber"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var uiLanguages = []language.Tag{language.English, language.French}
+
+var printer = message.NewPrinter(language.English)
+
+var frenchMessages = map[string]string{
+	"Valorant Shopwatcher - Currently running": "Valorant Shopwatcher - En cours d'exécution",
+	"Exit":                                   "Quitter",
+	"Error":                                  "Erreur",
+	"%s is available in your Valorant shop!": "%s est disponible dans votre boutique Valorant !",
+	"%s is available in your night market!":  "%s est disponible dans votre marché nocturne !",
+	"%d watch rules could not be evaluated":  "%d règles de surveillance n'ont pas pu être évaluées",
+	"Max price":                              "Prix maximum",
+	"Only notify me when the price is at most (VP, 0 for no limit):": "Me prévenir seulement si le prix est d'au plus (VP, 0 pour aucune limite) :",
+	"Save":                                "Enregistrer",
+	"Login":                               "Connexion",
+	"Username:":                           "Nom d'utilisateur :",
+	"Password:":                           "Mot de passe :",
+	"Region:":                             "Région :",
+	"Log in":                              "Se connecter",
+	"Invalid credentials":                 "Identifiants invalides",
+	"Multi-factor authentication enabled": "Authentification multifacteur activée",
+	"Please enter MFA code given by Riot Games": "Veuillez saisir le code d'authentification envoyé par Riot Games",
+	"Submit code":                              "Valider le code",
+	"Couldn't connect with MFA":                "Impossible de se connecter avec l'authentification multifacteur",
+	"Import wishlist":                          "Importer une liste de souhaits",
+	"Export wishlist":                          "Exporter la liste de souhaits",
+	"Wishlist files":                           "Listes de souhaits",
+	"The app could not read the wishlist file": "L'application n'a pas pu lire le fichier de liste de souhaits",
+	"The app could not export your wishlist":   "L'application n'a pas pu exporter votre liste de souhaits",
+	"%d skins found.\n\nYes: add them to your wishlist\nNo: replace your wishlist with them": "%d skins trouvés.\n\nOui : les ajouter à votre liste de souhaits\nNon : remplacer votre liste de souhaits par ceux-ci",
+	"The app could not import the wishlist":                                                  "L'application n'a pas pu importer la liste de souhaits",
+	"%d skins imported.":                                                                     "%d skins importés.",
+	"%d skins were already in your wishlist.":                                                "%d skins étaient déjà dans votre liste de souhaits.",
+	"%d watch rules imported.":                                                               "%d règles de surveillance importées.",
+	"%d unknown skins were skipped:":                                                         "%d skins inconnus ont été ignorés :",
+	"The app could not create a wishlist code":                                               "L'application n'a pas pu créer de code de liste de souhaits",
+	"Share code":                          "Code de partage",
+	"Your wishlist code:":                 "Votre code de liste de souhaits :",
+	"Copy":                                "Copier",
+	"The app could not copy the code":     "L'application n'a pas pu copier le code",
+	"Paste a wishlist code to import it:": "Collez un code de liste de souhaits pour l'importer :",
+	"Import":                              "Importer",
+	"The app could not read the wishlist code": "L'application n'a pas pu lire le code de liste de souhaits",
+	"Watch rules": "Règles de surveillance",
+	"Variables: %s. Functions: contains(text, part), lower(text).\nExample: weapon == 'Vandal' && tier == 'Exclusive', or nightMarket && discount >= 35": "Variables : %s. Fonctions : contains(texte, partie), lower(texte).\nExemple : weapon == 'Vandal' && tier == 'Exclusive', ou nightMarket && discount >= 35",
+	"Name:":                           "Nom :",
+	"Expression:":                     "Expression :",
+	"Priority:":                       "Priorité :",
+	"Max price (VP, 0 for no limit):": "Prix maximum (VP, 0 pour aucune limite) :",
+	"Add rule":                        "Ajouter la règle",
+	"This rule is not valid":          "Cette règle n'est pas valide",
+	"Remove rule":                     "Supprimer la règle",
+	"Close":                           "Fermer",
+	"The app could not apply your settings, defaults are used instead": "L'application n'a pas pu appliquer vos paramètres, les valeurs par défaut sont utilisées",
+	"All skins": "Tous les skins",
+	"Search skins, e.g. \"prime vandal\" or \"knife\"": "Rechercher des skins, par ex. « prime vandal » ou « knife »",
+	"My wishlist":                        "Ma liste de souhaits",
+	"Set max price...":                   "Définir le prix maximum...",
+	"Import...":                          "Importer...",
+	"Export...":                          "Exporter...",
+	"Share code...":                      "Code de partage...",
+	"Rules...":                           "Règles...",
+	"My current shop":                    "Ma boutique actuelle",
+	"Must-have":                          "Indispensable",
+	"Nice to have":                       "Souhaité",
+	"Watching":                           "À surveiller",
+	"The app could not load saved skins": "L'application n'a pas pu charger les skins enregistrés",
+	"The app could not load saved skins, the original file was kept as %s": "L'application n'a pas pu charger les skins enregistrés, le fichier d'origine a été conservé sous %s",
+	"The app could not back up saved skins before upgrading them":          "L'application n'a pas pu sauvegarder les skins enregistrés avant leur mise à jour",
+	"The app could not fetch skins":                                        "L'application n'a pas pu récupérer les skins",
+	"The app could not save the data":                                      "L'application n'a pas pu enregistrer les données",
+	"The app could not open the video":                                     "L'application n'a pas pu ouvrir la vidéo",
+	"The app could not save your credentials":                              "L'application n'a pas pu enregistrer vos identifiants",
+	"The app could not log you in":                                         "L'application n'a pas pu vous connecter",
+	"Your username or password is incorrect":                               "Votre nom d'utilisateur ou votre mot de passe est incorrect",
+	"Too many login attempts, please try again later":                      "Trop de tentatives de connexion, veuillez réessayer plus tard",
+	"The multi-factor authentication code is incorrect":                    "Le code d'authentification multifacteur est incorrect",
+	"Your session has expired, please log in again":                        "Votre session a expiré, veuillez vous reconnecter",
+	"Riot servers are under maintenance":                                   "Les serveurs de Riot sont en maintenance",
+	"Access was denied by Riot servers":                                    "L'accès a été refusé par les serveurs de Riot",
+	"Riot servers could not find the requested data":                       "Les serveurs de Riot n'ont pas trouvé les données demandées",
+	"Riot servers are rate limiting requests, please try again later":      "Les serveurs de Riot limitent les requêtes, veuillez réessayer plus tard",
+	"Some notifications could not be sent":                                 "Certaines notifications n'ont pas pu être envoyées",
+	"Open video":                                                           "Voir la vidéo",
+	"Dismiss for today":                                                    "Ignorer pour aujourd'hui",
+	"Price":                                                                "Prix",
+	"Account":                                                              "Compte",
+	"Time left":                                                            "Temps restant",
+	"Rule":                                                                 "Règle",
+	"Video":                                                                "Vidéo",
+	"Watch the video":                                                      "Voir la vidéo",
+	"Your Valorant shop of %s":                                             "Votre boutique Valorant du %s",
+	"Skin":                                                                 "Skin",
+	"Wishlist":                                                             "Liste de souhaits",
+	"night market":                                                         "marché nocturne",
+	"%d wishlist skins in your Valorant shop":                              "%d skins de votre liste de souhaits dans votre boutique Valorant",
+	"Your Valorant shop":                                                   "Votre boutique Valorant",
+	"Your night market":                                                    "Votre marché nocturne",
+	"Your wishlist":                                                        "Votre liste de souhaits",
+	"Your wishlist is empty.":                                              "Votre liste de souhaits est vide.",
+	"Commands: /shop, /nightmarket, /wishlist":                             "Commandes : /shop, /nightmarket, /wishlist",
+	"No data yet, the shop has not been fetched.":                          "Aucune donnée pour l'instant, la boutique n'a pas encore été récupérée.",
+	"Snooze notifications...":                                              "Suspendre les notifications...",
+	"Snooze notifications for %s":                                          "Suspendre les notifications pour %s",
+	"Snooze...":                                                            "Suspendre...",
+	"Snooze %s":                                                            "Suspendre %s",
+	"Snooze %d skins":                                                      "Suspendre %d skins",
+	"Do not notify me for this many days (0 to stop snoozing):":            "Ne pas me prévenir pendant ce nombre de jours (0 pour reprendre) :",
+	"A notification could not be sent to %s":                               "Une notification n'a pas pu être envoyée à %s",
+	"Delivery log...":                                                      "Journal d'envoi...",
+	"Delivery log":                                                         "Journal d'envoi",
+	"The app could not read the delivery log":                              "L'application n'a pas pu lire le journal d'envoi",
+	"No notification has been sent yet.":                                   "Aucune notification n'a encore été envoyée.",
+	"%d notifications waiting to be sent":                                  "%d notifications en attente d'envoi",
+	"Night market:":                                                        "Marché nocturne :",
+	"Bundle: %s":                                                           "Pack : %s",
+	"Riot servers are currently unavailable":                               "Les serveurs de Riot sont actuellement indisponibles",
+	"Priority":                                                             "Priorité",
+}
+
+func init() {
+	for key, translation := range frenchMessages {
+		message.SetString(language.French, key, translation)
+	}
+}
+
+func setupLanguage(override string) {
+	locale = override
+	if locale == "" {
+		var err error
+		if locale, err = lang.DetectIETF(); err != nil {
+			locale = "en-US"
+		}
+	}
+	tag, _ := language.MatchStrings(language.NewMatcher(uiLanguages), locale)
+	printer = message.NewPrinter(tag)
+}
+
+func tr(key string, args ...interface{}) string {
+	return printer.Sprintf(key, args...)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func useLanguage(t *testing.T, override string) {
+	previousPrinter, previousLocale := printer, locale
+	t.Cleanup(func() { printer, locale = previousPrinter, previousLocale })
+	setupLanguage(override)
+}
+
+func TestTr(t *testing.T) {
+	tests := []struct {
+		locale string
+		key    string
+		args   []interface{}
+		want   string
+	}{
+		{"en-US", "Exit", nil, "Exit"},
+		{"fr-FR", "Exit", nil, "Quitter"},
+		{"fr-CA", "Exit", nil, "Quitter"},
+		{"de-DE", "Exit", nil, "Exit"},
+		{"fr-FR", "%s is available in your Valorant shop!", []interface{}{"Prime Vandal"}, "Prime Vandal est disponible dans votre boutique Valorant !"},
+		{"fr-FR", "%d notifications waiting to be sent", []interface{}{3}, "3 notifications en attente d'envoi"},
+		{"fr-FR", "Not translated %s", []interface{}{"yet"}, "Not translated yet"},
+	}
+	for _, test := range tests {
+		t.Run(test.locale+" "+test.key, func(t *testing.T) {
+			useLanguage(t, test.locale)
+			if got := tr(test.key, test.args...); got != test.want {
+				t.Errorf("tr(%q) = %q, want %q", test.key, got, test.want)
+			}
+		})
+	}
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestFrenchMessagesKeepFormatVerbs(t *testing.T) {
+	for key, translation := range frenchMessages {
+		if got, want := formatVerb.FindAllString(translation, -1), formatVerb.FindAllString(key, -1); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("%q is translated with the verbs %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestFrenchMessagesCoverEveryTr(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileSet := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fileSet, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if name, ok := call.Fun.(*ast.Ident); !ok || name.Name != "tr" {
+				return true
+			}
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			key, err := strconv.Unquote(literal.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := frenchMessages[key]; !ok {
+				t.Errorf("%s: %q has no French translation", fileSet.Position(literal.Pos()), key)
+			}
+			return true
+		})
+	}
+}
@@ -1,32 +1,60 @@
+//go:build windows
+
 package main
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
+	"context"
 	"log"
-	"net/http"
+	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
-	lang "github.com/cloudfoundry-attic/jibber_jabber"
 	"github.com/emersion/go-autostart"
 	"github.com/lxn/walk"
 	. "github.com/lxn/walk/declarative"
 	"github.com/lxn/win"
-	"github.com/robfig/cron"
 )
 
-var globalStore = GlobalStore{}
-var locale string
+type SkinLayout struct {
+	LinkLabel *walk.LinkLabel
+}
+
+type UiElems struct {
+	skinsListBox         MultiSelectList
+	selectedSkinsListBox MultiSelectList
+	shop                 *walk.Composite
+	mainWindow           *walk.MainWindow
+	skinLayouts          []SkinLayout
+	searchLineEdit       *walk.LineEdit
+	notifyIcon           *walk.NotifyIcon
+}
+
+func (skinLayout *SkinLayout) setData(skinName string, skinUrl string) {
+	if skinUrl != "" {
+		skinLayout.LinkLabel.SetText("<a href=\"" + skinUrl + "\">" + skinName + "</a>")
+	} else {
+		skinLayout.LinkLabel.SetText(skinName)
+	}
+}
+
+func displayError(message string) {
+	walk.MsgBox(nil, tr("Error"), message, walk.MsgBoxIconError)
+	if globalStore.Ui.mainWindow == nil {
+		return
+	}
+	globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
+		globalStore.Ui.mainWindow.Show()
+	})
+}
 
 func createNotifyIcon() {
 	ni, err := walk.NewNotifyIcon(globalStore.Ui.mainWindow)
 	if err != nil {
 		log.Fatal(err)
 	}
-	ni.SetToolTip("Valorant Shopwatcher - Currently running")
+	ni.SetToolTip(tr("Valorant Shopwatcher - Currently running"))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -44,11 +72,37 @@ func createNotifyIcon() {
 			win.ShowWindow(globalStore.Ui.mainWindow.Handle(), win.SW_RESTORE)
 		}
 	})
+	snoozeAction := walk.NewAction()
+	if err := snoozeAction.SetText(tr("Snooze notifications...")); err != nil {
+		log.Fatal(err)
+	}
+	snoozeAction.Triggered().Attach(func() {
+		account := globalStore.User.Login
+		drawSnoozeDialog(globalStore.Ui.mainWindow, tr("Snooze notifications for %s", account), globalStore.Snoozes.AccountSnoozedUntil(account), func(until time.Time) error {
+			return globalStore.Snoozes.SnoozeAccount(account, until)
+		})
+	})
+	if err := ni.ContextMenu().Actions().Add(snoozeAction); err != nil {
+		log.Fatal(err)
+	}
+	deliveryLogAction := walk.NewAction()
+	if err := deliveryLogAction.SetText(tr("Delivery log...")); err != nil {
+		log.Fatal(err)
+	}
+	deliveryLogAction.Triggered().Attach(func() {
+		drawDeliveryLogDialog(globalStore.Ui.mainWindow)
+	})
+	if err := ni.ContextMenu().Actions().Add(deliveryLogAction); err != nil {
+		log.Fatal(err)
+	}
 	exitAction := walk.NewAction()
-	if err := exitAction.SetText("Exit"); err != nil {
+	if err := exitAction.SetText(tr("Exit")); err != nil {
 		log.Fatal(err)
 	}
-	exitAction.Triggered().Attach(func() { walk.App().Exit(0) })
+	exitAction.Triggered().Attach(func() {
+		cancelAppContext()
+		walk.App().Exit(0)
+	})
 	if err := ni.ContextMenu().Actions().Add(exitAction); err != nil {
 		log.Fatal(err)
 	}
@@ -69,7 +123,7 @@ func createAllCompositesForSkins(skinLayouts *[]SkinLayout) []Widget {
 					AssignTo: &(*skinLayouts)[i].LinkLabel,
 					Text:     "",
 					OnLinkActivated: func(link *walk.LinkLabelLink) {
-						exec.Command("explorer", link.URL()).Start()
+						openURL(link.URL())
 					},
 				},
 			},
@@ -78,15 +132,159 @@ func createAllCompositesForSkins(skinLayouts *[]SkinLayout) []Widget {
 	return composites
 }
 
-func notifyUserIfTheyHaveWantedSkins(notifyIcon *walk.NotifyIcon) {
-	for _, skin := range globalStore.Ui.selectedSkinsListBox.AllSkins {
-		for _, storeSkin := range globalStore.CurrentShop {
-			if skin.Id == storeSkin.Id {
-				skinLocalizedName, _ := skin.LocalizedNames.Load(locale)
-				notifyIcon.ShowInfo("Valorant Shopwatcher", skinLocalizedName.(string)+" is available in your Valorant shop!")
+func filterSkinsList(query string) {
+	list := &globalStore.Ui.skinsListBox
+	if strings.TrimSpace(query) == "" || globalStore.SearchIndex == nil {
+		list.ClearFilter()
+		return
+	}
+	var skins []Skin
+	for _, result := range globalStore.SearchIndex.Search(query, 0) {
+		skins = append(skins, result.Skin)
+	}
+	list.Filter(skins)
+}
+
+func setSelectedWishlistPriority(priority Priority) {
+	list := &globalStore.Ui.selectedSkinsListBox
+	for _, index := range list.SelectedIndexes() {
+		list.AllSkins[index].Priority = priority
+	}
+	list.PublishItemsReset()
+	saveSkinsData()
+}
+
+func drawMaxPriceDialog(owner walk.Form) {
+	list := &globalStore.Ui.selectedSkinsListBox
+	indexes := list.SelectedIndexes()
+	if len(indexes) == 0 {
+		return
+	}
+	var dialog *walk.Dialog
+	var outNEPrice *walk.NumberEdit
+	Dialog{
+		AssignTo: &dialog,
+		Title:    tr("Max price"),
+		MinSize:  Size{Width: 250, Height: 120},
+		Layout:   VBox{},
+		Children: []Widget{
+			Label{
+				Text: tr("Only notify me when the price is at most (VP, 0 for no limit):"),
+			},
+			NumberEdit{
+				AssignTo: &outNEPrice,
+				Decimals: 0,
+				MinValue: 0,
+				MaxValue: 100000,
+				Value:    float64(list.AllSkins[indexes[0]].MaxPrice),
+			},
+			PushButton{
+				Text: tr("Save"),
+				OnClicked: func() {
+					for _, index := range indexes {
+						list.AllSkins[index].MaxPrice = int(outNEPrice.Value())
+					}
+					list.PublishItemsReset()
+					saveSkinsData()
+					dialog.Accept()
+				},
+			},
+		},
+	}.Run(owner)
+}
+
+func drawDeliveryLogDialog(owner walk.Form) {
+	entries, err := globalStore.DeliveryLog.Recent(200)
+	if err != nil {
+		showError(tr("The app could not read the delivery log"), err)
+		return
+	}
+	lines := make([]string, 0, len(entries)+2)
+	for index := len(entries) - 1; index >= 0; index-- {
+		lines = append(lines, entries[index].String())
+	}
+	if len(lines) == 0 {
+		lines = append(lines, tr("No notification has been sent yet."))
+	}
+	if pending := globalStore.Outbox.Pending(); pending > 0 {
+		lines = append([]string{tr("%d notifications waiting to be sent", pending), ""}, lines...)
+	}
+	var dialog *walk.Dialog
+	Dialog{
+		AssignTo: &dialog,
+		Title:    tr("Delivery log"),
+		MinSize:  Size{Width: 700, Height: 400},
+		Layout:   VBox{},
+		Children: []Widget{
+			TextEdit{
+				Text:     strings.Join(lines, "\r\n"),
+				ReadOnly: true,
+				VScroll:  true,
+			},
+			PushButton{
+				Text:      tr("Close"),
+				OnClicked: func() { dialog.Accept() },
+			},
+		},
+	}.Run(owner)
+}
+
+func drawSnoozeDialog(owner walk.Form, title string, current time.Time, apply func(until time.Time) error) {
+	var dialog *walk.Dialog
+	var outNEDays *walk.NumberEdit
+	days := 1.0
+	if remaining := time.Until(current); remaining > 0 {
+		days = math.Ceil(remaining.Hours() / 24)
+	}
+	Dialog{
+		AssignTo: &dialog,
+		Title:    title,
+		MinSize:  Size{Width: 250, Height: 120},
+		Layout:   VBox{},
+		Children: []Widget{
+			Label{
+				Text: tr("Do not notify me for this many days (0 to stop snoozing):"),
+			},
+			NumberEdit{
+				AssignTo: &outNEDays,
+				Decimals: 0,
+				MinValue: 0,
+				MaxValue: 365,
+				Value:    days,
+			},
+			PushButton{
+				Text: tr("Save"),
+				OnClicked: func() {
+					if err := apply(time.Now().AddDate(0, 0, int(outNEDays.Value()))); err != nil {
+						showError(tr("The app could not save the data"), err)
+						return
+					}
+					dialog.Accept()
+				},
+			},
+		},
+	}.Run(owner)
+}
+
+func snoozeSelectedWishlistSkins(owner walk.Form) {
+	list := &globalStore.Ui.selectedSkinsListBox
+	indexes := list.SelectedIndexes()
+	if len(indexes) == 0 {
+		return
+	}
+	first := list.AllSkins[indexes[0]]
+	title := tr("Snooze %s", localizedName(first, locale))
+	if len(indexes) > 1 {
+		title = tr("Snooze %d skins", len(indexes))
+	}
+	drawSnoozeDialog(owner, title, globalStore.Snoozes.SkinSnoozedUntil(first.Id), func(until time.Time) error {
+		for _, index := range indexes {
+			if err := globalStore.Snoozes.SnoozeSkin(list.AllSkins[index].Id, until); err != nil {
+				return err
 			}
 		}
-	}
+		return nil
+	})
 }
 
 func drawUserform(owner walk.Form) {
@@ -97,7 +295,7 @@ func drawUserform(owner walk.Form) {
 	globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
 		Dialog{
 			AssignTo: &userForm,
-			Title:    "Login",
+			Title:    tr("Login"),
 			MinSize:  Size{Width: 200, Height: 250},
 			Layout:   VBox{},
 			Children: []Widget{
@@ -105,7 +303,7 @@ func drawUserform(owner walk.Form) {
 					Layout: HBox{},
 					Children: []Widget{
 						Label{
-							Text: "Username:",
+							Text: tr("Username:"),
 						},
 						LineEdit{
 							Name:     "Username",
@@ -117,7 +315,7 @@ func drawUserform(owner walk.Form) {
 					Layout: HBox{},
 					Children: []Widget{
 						Label{
-							Text: "Password:",
+							Text: tr("Password:"),
 						},
 						LineEdit{
 							Name:         "Password",
@@ -130,7 +328,7 @@ func drawUserform(owner walk.Form) {
 					Layout: HBox{},
 					Children: []Widget{
 						Label{
-							Text: "Region:",
+							Text: tr("Region:"),
 						},
 						ComboBox{
 							Name:     "Region",
@@ -140,12 +338,12 @@ func drawUserform(owner walk.Form) {
 					},
 				},
 				PushButton{
-					Text: "Log in",
+					Text: tr("Log in"),
 					OnClicked: func() {
 						globalStore.User = User{Login: outLELogin.Text(), Password: outLEPassword.Text(), Region: outCBRegion.Text()}
 						var err error
 						if err != nil {
-							walk.MsgBox(nil, "Error", "Invalid credentials", walk.MsgBoxIconError)
+							walk.MsgBox(nil, tr("Error"), tr("Invalid credentials"), walk.MsgBoxIconError)
 							globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
 								globalStore.Ui.mainWindow.Show()
 								userForm.Run()
@@ -172,24 +370,35 @@ func drawUserform(owner walk.Form) {
 
 func drawShop() {
 	for index, skinLayout := range globalStore.Ui.skinLayouts {
-		res, _ := globalStore.CurrentShop[index].LocalizedNames.Load(locale)
-		skinLayout.setData(res.(string), globalStore.CurrentShop[index].Video)
+		if index >= len(globalStore.CurrentShop) {
+			skinLayout.setData("", "")
+			continue
+		}
+		skinLayout.setData(localizedName(globalStore.CurrentShop[index], locale), globalStore.CurrentShop[index].Video)
 	}
-	notifyUserIfTheyHaveWantedSkins(globalStore.Ui.notifyIcon)
+	notifyUserIfTheyHaveWantedSkins()
+}
+
+func promptLogin() {
+	drawUserform(globalStore.Ui.mainWindow)
+}
+
+func promptMfaCode(ctx context.Context, codeLength int) error {
+	return drawMfaModal(ctx, globalStore.Ui.mainWindow, codeLength)
 }
 
-func drawMfaModal(owner walk.Form, codeLength int) {
+func drawMfaModal(ctx context.Context, owner walk.Form, codeLength int) error {
 	var outLECode *walk.LineEdit
 	var mfa *walk.Dialog
 	globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
 		Dialog{
 			AssignTo: &mfa,
-			Title:    "Multi-factor authentication enabled",
+			Title:    tr("Multi-factor authentication enabled"),
 			MinSize:  Size{Width: 200, Height: 250},
 			Layout:   VBox{},
 			Children: []Widget{
 				Label{
-					Text: "Please enter MFA code given by Riot Games",
+					Text: tr("Please enter MFA code given by Riot Games"),
 				},
 				LineEdit{
 					AssignTo:  &outLECode,
@@ -197,30 +406,12 @@ func drawMfaModal(owner walk.Form, codeLength int) {
 					MaxLength: codeLength,
 				},
 				PushButton{
-					Text: "Submit code",
+					Text: tr("Submit code"),
 					OnClicked: func() {
-						body, _ := json.Marshal(MFABody{Type: "multifactor", Code: outLECode.Text(), RememberDevice: false})
-						req, _ := http.NewRequest("PUT", "https://auth.riotgames.com/api/v1/authorization", bytes.NewBuffer(body))
-						setRequestHeaders(req)
-						res, err := client.Do(req)
-						if err != nil {
-							walk.MsgBox(nil, "Error", "Couldn't connect with MFA", walk.MsgBoxIconError)
-							globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-								globalStore.Ui.mainWindow.Show()
-							})
-						}
-						defer res.Body.Close()
-						var accessTokenContainer AccessTokenContainer
-						data, _ := io.ReadAll(res.Body)
-						err = json.Unmarshal(data, &accessTokenContainer)
-						if err != nil {
-							walk.MsgBox(nil, "Error", "Couldn't connect with MFA", walk.MsgBoxIconError)
-							globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-								globalStore.Ui.mainWindow.Show()
-							})
+						if err := submitMfaCode(ctx, outLECode.Text()); err != nil {
+							showError(tr("Couldn't connect with MFA"), err)
+							return
 						}
-						globalStore.User.AccessToken = accessTokenContainer.Response.Parameters.Uri.Query().Get("access_token")
-						saveUserData(globalStore.User)
 						globalStore.Channels.MFAToken <- true
 						mfa.Close(-1)
 					},
@@ -235,16 +426,214 @@ func drawMfaModal(owner walk.Form, codeLength int) {
 		globalStore.Ui.mainWindow.Show()
 		mfa.Run()
 	})
-	<-globalStore.Channels.MFAToken
+	select {
+	case <-globalStore.Channels.MFAToken:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func startCron() {
-	c := cron.New()
-	c.AddFunc("0 0 2 ? * *", func() {
-		seedUser()
-		notifyUserIfTheyHaveWantedSkins(globalStore.Ui.notifyIcon)
-	})
-	c.Start()
+func importWishlistFromFile(owner walk.Form) {
+	dlg := &walk.FileDialog{Title: tr("Import wishlist"), Filter: tr("Wishlist files") + " (*.json;*.csv)|*.json;*.csv"}
+	if ok, err := dlg.ShowOpen(owner); err != nil || !ok {
+		return
+	}
+	data, err := os.ReadFile(dlg.FilePath)
+	if err != nil {
+		showError(tr("The app could not read the wishlist file"), err)
+		return
+	}
+	entries, rules, err := decodeWishlistFile(dlg.FilePath, data)
+	if err != nil {
+		showError(tr("The app could not read the wishlist file"), err)
+		return
+	}
+	applyImportedWishlist(owner, entries, rules)
+}
+
+func exportWishlistToFile(owner walk.Form) {
+	dlg := &walk.FileDialog{Title: tr("Export wishlist"), Filter: "JSON (*.json)|*.json|CSV (*.csv)|*.csv", FilePath: "wishlist.json"}
+	if ok, err := dlg.ShowSave(owner); err != nil || !ok {
+		return
+	}
+	path := dlg.FilePath
+	if filepath.Ext(path) == "" {
+		if dlg.FilterIndex == 2 {
+			path += ".csv"
+		} else {
+			path += ".json"
+		}
+	}
+	if err := exportWishlist(path, globalStore.Ui.selectedSkinsListBox.AllSkins, globalStore.Rules); err != nil {
+		showError(tr("The app could not export your wishlist"), err)
+	}
+}
+
+func applyImportedWishlist(owner walk.Form, entries []WishlistSkin, rules []WatchRule) {
+	answer := walk.MsgBox(owner, tr("Import wishlist"), tr("%d skins found.\n\nYes: add them to your wishlist\nNo: replace your wishlist with them", len(entries)), walk.MsgBoxYesNoCancel|walk.MsgBoxIconQuestion)
+	if answer != win.IDYES && answer != win.IDNO {
+		return
+	}
+	skins, result, err := importWishlist(entries, globalStore.Ui.skinsListBox.AllSkins, globalStore.Ui.selectedSkinsListBox.AllSkins, answer == win.IDNO)
+	if err != nil {
+		showError(tr("The app could not import the wishlist"), err)
+		return
+	}
+	var importedRules int
+	globalStore.Rules, importedRules = importRules(rules, globalStore.Rules, answer == win.IDNO)
+	globalStore.Ui.selectedSkinsListBox.FeedList(skins)
+	saveSkinsData()
+	message := tr("%d skins imported.", result.Imported)
+	if importedRules > 0 {
+		message += "\n" + tr("%d watch rules imported.", importedRules)
+	}
+	if result.AlreadyKnown > 0 {
+		message += "\n" + tr("%d skins were already in your wishlist.", result.AlreadyKnown)
+	}
+	if len(result.UnknownIds) > 0 {
+		message += "\n" + tr("%d unknown skins were skipped:", len(result.UnknownIds)) + "\n" + strings.Join(result.UnknownIds, "\n")
+	}
+	walk.MsgBox(owner, tr("Import wishlist"), message, walk.MsgBoxIconInformation)
+}
+
+func drawShareCodeDialog(owner walk.Form) {
+	code, err := encodeShareCode(globalStore.Ui.selectedSkinsListBox.AllSkins)
+	if err != nil {
+		showError(tr("The app could not create a wishlist code"), err)
+		return
+	}
+	var dialog *walk.Dialog
+	var outLECode *walk.LineEdit
+	Dialog{
+		AssignTo: &dialog,
+		Title:    tr("Share code"),
+		MinSize:  Size{Width: 400, Height: 200},
+		Layout:   VBox{},
+		Children: []Widget{
+			Label{
+				Text: tr("Your wishlist code:"),
+			},
+			LineEdit{
+				Text:     code,
+				ReadOnly: true,
+			},
+			PushButton{
+				Text: tr("Copy"),
+				OnClicked: func() {
+					if err := walk.Clipboard().SetText(code); err != nil {
+						showError(tr("The app could not copy the code"), err)
+					}
+				},
+			},
+			Label{
+				Text: tr("Paste a wishlist code to import it:"),
+			},
+			LineEdit{
+				AssignTo: &outLECode,
+			},
+			PushButton{
+				Text: tr("Import"),
+				OnClicked: func() {
+					entries, err := decodeShareCode(outLECode.Text())
+					if err != nil {
+						showError(tr("The app could not read the wishlist code"), err)
+						return
+					}
+					applyImportedWishlist(dialog, entries, nil)
+					dialog.Accept()
+				},
+			},
+		},
+	}.Run(owner)
+}
+
+func drawRulesDialog(owner walk.Form) {
+	var dialog *walk.Dialog
+	var rulesListBox *walk.ListBox
+	var outLEName *walk.LineEdit
+	var outLEExpression *walk.LineEdit
+	var outCBPriority *walk.ComboBox
+	var outNEPrice *walk.NumberEdit
+	ruleDescriptions := func() []string {
+		var descriptions []string
+		for _, rule := range globalStore.Rules {
+			descriptions = append(descriptions, rule.String())
+		}
+		return descriptions
+	}
+	var priorityLabels []string
+	for _, priority := range priorities {
+		priorityLabels = append(priorityLabels, priority.Label())
+	}
+	Dialog{
+		AssignTo: &dialog,
+		Title:    tr("Watch rules"),
+		MinSize:  Size{Width: 600, Height: 400},
+		Layout:   VBox{},
+		Children: []Widget{
+			ListBox{
+				AssignTo: &rulesListBox,
+				Model:    ruleDescriptions(),
+			},
+			Label{
+				Text: tr("Variables: %s. Functions: contains(text, part), lower(text).\nExample: weapon == 'Vandal' && tier == 'Exclusive', or nightMarket && discount >= 35", strings.Join(ruleVariables, ", ")),
+			},
+			Composite{
+				Layout: Grid{Columns: 2},
+				Children: []Widget{
+					Label{Text: tr("Name:")},
+					LineEdit{AssignTo: &outLEName},
+					Label{Text: tr("Expression:")},
+					LineEdit{AssignTo: &outLEExpression},
+					Label{Text: tr("Priority:")},
+					ComboBox{AssignTo: &outCBPriority, Model: priorityLabels, CurrentIndex: 1},
+					Label{Text: tr("Max price (VP, 0 for no limit):")},
+					NumberEdit{AssignTo: &outNEPrice, Decimals: 0, MinValue: 0, MaxValue: 100000},
+				},
+			},
+			Composite{
+				Layout: HBox{},
+				Children: []Widget{
+					PushButton{
+						Text: tr("Add rule"),
+						OnClicked: func() {
+							rule := WatchRule{Name: outLEName.Text(), Expression: outLEExpression.Text(), Priority: priorities[outCBPriority.CurrentIndex()], MaxPrice: int(outNEPrice.Value())}
+							if rule.Name == "" {
+								rule.Name = rule.Expression
+							}
+							if err := rule.precompile(); err != nil {
+								showError(tr("This rule is not valid"), err)
+								return
+							}
+							globalStore.Rules = append(globalStore.Rules, rule)
+							rulesListBox.SetModel(ruleDescriptions())
+							outLEName.SetText("")
+							outLEExpression.SetText("")
+							saveSkinsData()
+						},
+					},
+					PushButton{
+						Text: tr("Remove rule"),
+						OnClicked: func() {
+							index := rulesListBox.CurrentIndex()
+							if index < 0 || index >= len(globalStore.Rules) {
+								return
+							}
+							globalStore.Rules = append(globalStore.Rules[:index], globalStore.Rules[index+1:]...)
+							rulesListBox.SetModel(ruleDescriptions())
+							saveSkinsData()
+						},
+					},
+					PushButton{
+						Text:      tr("Close"),
+						OnClicked: func() { dialog.Accept() },
+					},
+				},
+			},
+		},
+	}.Run(owner)
+	notifyUserIfTheyHaveWantedSkins()
 }
 
 func runAppOnStartup() {
@@ -267,22 +656,19 @@ func runAppOnStartup() {
 	}
 }
 
-func setupChannels() {
-	globalStore.Channels.MFAToken = make(chan bool)
-	globalStore.Channels.NewToken = make(chan string)
-	globalStore.Channels.LoginWindow = make(chan bool)
-}
-
 //go:generate go-winres make --product-version=dev
 
 func main() {
-	runAppOnStartup()
-	var err error
-	if locale, err = lang.DetectIETF(); err != nil {
-		locale = "en-US"
+	options := parseAppOptions()
+	startupErrors := setupApp(options)
+	if options.searchQuery != "" {
+		runSearchCommand(appContext, options.searchQuery)
+		return
 	}
+	runAppOnStartup()
 	setupChannels()
 	globalStore.User, _ = loadSavedUser()
+	globalStore.Ui.selectedSkinsListBox.ShowPriorities = true
 	loadSavedSkins()
 	rect := win.RECT{}
 	win.GetWindowRect(win.GetDesktopWindow(), &rect)
@@ -304,7 +690,14 @@ func main() {
 						Layout: VBox{},
 						Children: []Widget{
 							Label{
-								Text: "All skins",
+								Text: tr("All skins"),
+							},
+							LineEdit{
+								CueBanner: tr("Search skins, e.g. \"prime vandal\" or \"knife\""),
+								OnTextChanged: func() {
+									filterSkinsList(globalStore.Ui.searchLineEdit.Text())
+								},
+								AssignTo: &globalStore.Ui.searchLineEdit,
 							},
 							ListBox{
 								Name:                     "Skins",
@@ -323,7 +716,7 @@ func main() {
 									globalStore.Ui.selectedSkinsListBox.InsertSelectedSkins(globalStore.Ui.skinsListBox.SelectedSkins)
 									globalStore.Ui.skinsListBox.SetSelectedIndexes([]int{})
 									saveSkinsData()
-									notifyUserIfTheyHaveWantedSkins(globalStore.Ui.notifyIcon)
+									notifyUserIfTheyHaveWantedSkins()
 								},
 							},
 							PushButton{
@@ -343,20 +736,72 @@ func main() {
 						Layout: VBox{},
 						Children: []Widget{
 							Label{
-								Text: "My wishlist",
+								Text: tr("My wishlist"),
 							},
 							ListBox{
 								MultiSelection:           true,
 								Model:                    &globalStore.Ui.selectedSkinsListBox,
 								AssignTo:                 &globalStore.Ui.selectedSkinsListBox.ListBox,
 								OnSelectedIndexesChanged: globalStore.Ui.selectedSkinsListBox.SelectedIndexesChanged,
+								ContextMenuItems: []MenuItem{
+									Action{
+										Text:        PriorityMustHave.Label(),
+										OnTriggered: func() { setSelectedWishlistPriority(PriorityMustHave) },
+									},
+									Action{
+										Text:        PriorityNiceToHave.Label(),
+										OnTriggered: func() { setSelectedWishlistPriority(PriorityNiceToHave) },
+									},
+									Action{
+										Text:        PriorityWatching.Label(),
+										OnTriggered: func() { setSelectedWishlistPriority(PriorityWatching) },
+									},
+									Separator{},
+									Action{
+										Text:        tr("Set max price..."),
+										OnTriggered: func() { drawMaxPriceDialog(globalStore.Ui.mainWindow) },
+									},
+									Action{
+										Text:        tr("Snooze..."),
+										OnTriggered: func() { snoozeSelectedWishlistSkins(globalStore.Ui.mainWindow) },
+									},
+								},
+							},
+							Composite{
+								Layout: HBox{MarginsZero: true},
+								Children: []Widget{
+									PushButton{
+										Text: tr("Import..."),
+										OnClicked: func() {
+											importWishlistFromFile(globalStore.Ui.mainWindow)
+										},
+									},
+									PushButton{
+										Text: tr("Export..."),
+										OnClicked: func() {
+											exportWishlistToFile(globalStore.Ui.mainWindow)
+										},
+									},
+									PushButton{
+										Text: tr("Share code..."),
+										OnClicked: func() {
+											drawShareCodeDialog(globalStore.Ui.mainWindow)
+										},
+									},
+									PushButton{
+										Text: tr("Rules..."),
+										OnClicked: func() {
+											drawRulesDialog(globalStore.Ui.mainWindow)
+										},
+									},
+								},
 							},
 						},
 					},
 				},
 			},
 			Label{
-				Text: "My current shop",
+				Text: tr("My current shop"),
 			},
 			Composite{
 				AssignTo: &globalStore.Ui.shop,
@@ -366,9 +811,15 @@ func main() {
 		},
 	}.Create()
 	createNotifyIcon()
-	go seedUser()
-	go feedData()
+	startupErrors = append(startupErrors, setupNotifiers()...)
+	for _, err := range startupErrors {
+		showError(tr("The app could not apply your settings, defaults are used instead"), err)
+	}
+	go seedUser(appContext)
+	go feedData(appContext)
 	go startCron()
+	go runOutbox(appContext)
 	globalStore.Ui.mainWindow.Hide()
 	globalStore.Ui.mainWindow.Run()
+	cancelAppContext()
 }
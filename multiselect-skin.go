@@ -1,45 +1,70 @@
+//go:build windows
+
 package main
 
 import (
-	"sort"
-
 	"github.com/lxn/walk"
 )
 
 type MultiSelectList struct {
 	*walk.ListBox
 	walk.ListModelBase
-	SelectedSkins []Skin
-	AllSkins      []Skin
+	SelectedSkins  []Skin
+	AllSkins       []Skin
+	ShowPriorities bool
+	filteredSkins  []Skin
+	isFiltered     bool
+}
+
+func (m *MultiSelectList) VisibleSkins() []Skin {
+	if m.isFiltered {
+		return m.filteredSkins
+	}
+	return m.AllSkins
+}
+
+func (m *MultiSelectList) Filter(skins []Skin) {
+	m.filteredSkins = skins
+	m.isFiltered = true
+	m.SetModel(m)
+}
+
+func (m *MultiSelectList) ClearFilter() {
+	if !m.isFiltered {
+		return
+	}
+	m.filteredSkins = nil
+	m.isFiltered = false
+	m.SetModel(m)
 }
 
 func (m *MultiSelectList) ItemCount() int {
-	return len(m.AllSkins)
+	return len(m.VisibleSkins())
 }
 
 func (m *MultiSelectList) Value(index int) interface{} {
-	var skinName any
-	var ok bool
-	if skinName, ok = m.AllSkins[index].LocalizedNames.Load(locale); !ok {
-		skinName, _ = m.AllSkins[index].LocalizedNames.Load("en-US")
-	}
-	if skinName == nil {
-		return nil
+	skins := m.VisibleSkins()
+	skinName := localizedName(skins[index], locale)
+	if !m.ShowPriorities {
+		return skinName
 	}
-	return skinName.(string)
+	return skinName + wishlistDecoration(skins[index])
 }
 
 func (m *MultiSelectList) FeedList(skins SortedSkins) {
-	sort.Sort(skins)
+	skins.Sort(locale)
 	m.AllSkins = skins
+	m.filteredSkins = nil
+	m.isFiltered = false
 	m.SetModel(m)
 }
 
 func (m *MultiSelectList) SelectedIndexesChanged() {
 	list := m.ListBox.SelectedIndexes()
+	skins := m.VisibleSkins()
 	m.SelectedSkins = make([]Skin, len(list))
 	for i, v := range list {
-		m.SelectedSkins[i] = m.AllSkins[v]
+		m.SelectedSkins[i] = skins[v]
 	}
 }
 
@@ -59,7 +84,7 @@ func (m *MultiSelectList) InsertSelectedSkins(skins []Skin) {
 			sortedSkins = append(sortedSkins, skin)
 		}
 	}
-	sort.Sort(sortedSkins)
+	sortedSkins.Sort(locale)
 	m.AllSkins = sortedSkins
 	m.SetModel(m)
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	notificationHistoryFile      = "notifications.json"
+	notificationHistoryRetention = 30 * 24 * time.Hour
+)
+
+type NotificationHistory struct {
+	mutex sync.Mutex
+	path  string
+	Sent  map[string]time.Time `json:"sent"`
+}
+
+func loadNotificationHistory(path string) (*NotificationHistory, error) {
+	history := &NotificationHistory{path: path, Sent: make(map[string]time.Time)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return history, err
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return &NotificationHistory{path: path, Sent: make(map[string]time.Time)}, err
+	}
+	if history.Sent == nil {
+		history.Sent = make(map[string]time.Time)
+	}
+	return history, nil
+}
+
+func matchRotation(match Match) string {
+	if match.ExpiresAt.IsZero() {
+		return time.Now().UTC().Format("2006-01-02")
+	}
+	return match.ExpiresAt.UTC().Round(time.Hour).Format(time.RFC3339)
+}
+
+func notificationKey(notification Notification, channel string) string {
+	if notification.Digest && notification.Summary != nil {
+		return digestKey(*notification.Summary, channel)
+	}
+	if notification.Summary != nil {
+		return strings.Join([]string{notification.Account, "summary", notification.Summary.Rotation, channel}, "|")
+	}
+	return strings.Join([]string{notification.Account, notification.Match.LevelId, matchRotation(notification.Match), channel}, "|")
+}
+
+func digestKey(snapshot ShopSnapshot, channel string) string {
+	return strings.Join([]string{snapshot.Account, "digest", snapshot.Rotation, channel}, "|")
+}
+
+func (history *NotificationHistory) WasSent(key string) bool {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	_, ok := history.Sent[key]
+	return ok
+}
+
+func (history *NotificationHistory) MarkSent(key string, at time.Time) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	history.Sent[key] = at
+}
+
+func (history *NotificationHistory) Save() error {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	for key, sentAt := range history.Sent {
+		if time.Since(sentAt) > notificationHistoryRetention {
+			delete(history.Sent, key)
+		}
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(history.path, data)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotificationKey(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 20, 0, 0, 3, 0, time.UTC)
+	today := time.Now().UTC().Format("2006-01-02")
+	tests := []struct {
+		name         string
+		notification Notification
+		channel      string
+		want         string
+	}{
+		{"match", Notification{Account: "player", Match: Match{LevelId: "LEVEL", ExpiresAt: expiresAt}}, "discord:1", "player|LEVEL|2026-10-20T00:00:00Z|discord:1"},
+		{"match rounded to the rotation hour", Notification{Account: "player", Match: Match{LevelId: "LEVEL", ExpiresAt: expiresAt.Add(-2 * time.Minute)}}, "discord:1", "player|LEVEL|2026-10-20T00:00:00Z|discord:1"},
+		{"match without an expiry", Notification{Account: "player", Match: Match{LevelId: "LEVEL"}}, "tray", "player|LEVEL|" + today + "|tray"},
+		{"summary", Notification{Account: "player", Match: Match{LevelId: "LEVEL"}, Summary: &ShopSnapshot{Rotation: "2026-10-20T00:00:00Z"}}, "ntfy", "player|summary|2026-10-20T00:00:00Z|ntfy"},
+		{"digest", Notification{Account: "player", Summary: &ShopSnapshot{Account: "player", Rotation: "2026-10-20T00:00:00Z"}, Digest: true}, "email:mail", "player|digest|2026-10-20T00:00:00Z|email:mail"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := notificationKey(test.notification, test.channel); got != test.want {
+				t.Errorf("notificationKey() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDigestKey(t *testing.T) {
+	snapshot := ShopSnapshot{Account: "player", Rotation: "2026-10-20T00:00:00Z"}
+	if got := digestKey(snapshot, "email:mail"); got != "player|digest|2026-10-20T00:00:00Z|email:mail" {
+		t.Errorf("digestKey() = %q", got)
+	}
+	if digestKey(snapshot, "email:mail") == notificationKey(Notification{Account: "player", Summary: &snapshot}, "email:mail") {
+		t.Error("digestKey() collides with the summary notification key")
+	}
+}
+
+func TestNotificationHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), notificationHistoryFile)
+	history, err := loadNotificationHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	history.MarkSent("recent", now)
+	history.MarkSent("expired", now.Add(-notificationHistoryRetention-time.Hour))
+	if !history.WasSent("recent") || !history.WasSent("expired") || history.WasSent("unknown") {
+		t.Errorf("WasSent() before Save = %v", history.Sent)
+	}
+	if err := history.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadNotificationHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"recent", true},
+		{"expired", false},
+		{"unknown", false},
+	}
+	for _, test := range tests {
+		if got := reloaded.WasSent(test.key); got != test.want {
+			t.Errorf("WasSent(%q) after reload = %v, want %v", test.key, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var secretQueryKeys = []string{"token", "key", "secret", "pass", "auth", "sig"}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func urlPassword(parsedUrl *url.URL) string {
+	if parsedUrl.User == nil {
+		return ""
+	}
+	password, _ := parsedUrl.User.Password()
+	return password
+}
+
+func urlPathSegments(parsedUrl *url.URL) []string {
+	return splitPath(strings.Trim(parsedUrl.Path, "/"))
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func redactNotificationURL(raw string) string {
+	scheme, rest, ok := strings.Cut(strings.TrimSpace(raw), "://")
+	if !ok {
+		return redacted
+	}
+	scheme = strings.ToLower(scheme)
+	authority, remainder := rest, ""
+	if index := strings.IndexAny(rest, "/?#"); index >= 0 {
+		authority, remainder = rest[:index], rest[index:]
+	}
+	if index := strings.LastIndex(authority, "@"); index >= 0 {
+		authority = redacted + "@" + authority[index+1:]
+	} else if scheme == "telegram" && authority != "" {
+		authority = redacted
+	}
+	remainder, fragment, hasFragment := strings.Cut(remainder, "#")
+	path, query, hasQuery := strings.Cut(remainder, "?")
+	redactAll := scheme == "http" || scheme == "https" || strings.HasPrefix(scheme, "webhook+")
+	segments := strings.Split(path, "/")
+	switch {
+	case redactAll || scheme == "discord":
+		for index := range segments {
+			if segments[index] != "" {
+				segments[index] = redacted
+			}
+		}
+	case scheme == "gotify" || scheme == "gotify+http":
+		for index := len(segments) - 1; index >= 0; index-- {
+			if segments[index] != "" {
+				segments[index] = redacted
+				break
+			}
+		}
+	}
+	redactedUrl := scheme + "://" + authority + strings.Join(segments, "/")
+	if hasQuery {
+		parameters := strings.Split(query, "&")
+		for index, parameter := range parameters {
+			key, _, hasValue := strings.Cut(parameter, "=")
+			if hasValue && (redactAll || isSecretQueryKey(key)) {
+				parameters[index] = key + "=" + redacted
+			}
+		}
+		redactedUrl += "?" + strings.Join(parameters, "&")
+	}
+	if hasFragment && fragment != "" {
+		redactedUrl += "#" + redacted
+	}
+	return redactedUrl
+}
+
+func isSecretQueryKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretQueryKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactURLError(err error) error {
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return &url.Error{Op: urlError.Op, URL: redactNotificationURL(urlError.URL), Err: urlError.Err}
+	}
+	return err
+}
+
+func parseNotificationURL(raw string, rootCAs *x509.CertPool) (Notifier, error) {
+	scheme, rest, ok := strings.Cut(strings.TrimSpace(raw), "://")
+	if !ok {
+		return nil, fmt.Errorf("%s is not a notification URL", redactNotificationURL(raw))
+	}
+	scheme = strings.ToLower(scheme)
+	if strings.HasPrefix(scheme, "webhook+") {
+		target := strings.TrimPrefix(scheme, "webhook+") + "://" + rest
+		host, _, _ := strings.Cut(rest, "/")
+		if index := strings.LastIndex(host, "@"); index >= 0 {
+			host = host[index+1:]
+		}
+		sum := sha256.Sum256([]byte(target))
+		return newWebhookNotifier(WebhookSettings{Name: fmt.Sprintf("%s-%x", host, sum[:4]), URL: target})
+	}
+	parsedUrl, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification URL: %w", redactURLError(err))
+	}
+	query := parsedUrl.Query()
+	segments := urlPathSegments(parsedUrl)
+	switch scheme {
+	case "discord":
+		if parsedUrl.Host == "" || len(segments) != 1 {
+			return nil, fmt.Errorf("%s: expected discord://<webhook id>/<webhook token>", redactNotificationURL(raw))
+		}
+		return newDiscordNotifier(DiscordSettings{WebhookURL: "https://discord.com/api/webhooks/" + parsedUrl.Host + "/" + segments[0]}), nil
+	case "smtp", "smtps":
+		settings := SMTPSettings{
+			Host:     parsedUrl.Hostname(),
+			Username: parsedUrl.User.Username(),
+			Password: urlPassword(parsedUrl),
+			From:     query.Get("from"),
+			To:       splitList(query.Get("to")),
+			Security: query.Get("security"),
+			Mode:     query.Get("mode"),
+		}
+		if scheme == "smtps" {
+			settings.Security = smtpSecurityTLS
+		}
+		if settings.From == "" {
+			settings.From = settings.Username
+		}
+		if port := parsedUrl.Port(); port != "" {
+			if settings.Port, err = strconv.Atoi(port); err != nil {
+				return nil, fmt.Errorf("%s: invalid port %q", redactNotificationURL(raw), port)
+			}
+		}
+		return newSmtpNotifier(settings, rootCAs)
+	case "ntfy", "ntfy+http":
+		if len(segments) != 1 {
+			return nil, fmt.Errorf("%s: expected ntfy://[token@]host/topic", redactNotificationURL(raw))
+		}
+		settings := NtfySettings{ServerURL: "https://" + parsedUrl.Host, Topic: segments[0], Tags: splitList(query.Get("tags"))}
+		if scheme == "ntfy+http" {
+			settings.ServerURL = "http://" + parsedUrl.Host
+		}
+		if parsedUrl.User != nil {
+			settings.Token = parsedUrl.User.Username()
+		}
+		return newNtfyNotifier(settings), nil
+	case "gotify", "gotify+http":
+		if len(segments) == 0 {
+			return nil, fmt.Errorf("%s: expected gotify://host[/path]/<application token>", redactNotificationURL(raw))
+		}
+		settings := GotifySettings{ServerURL: "https://" + parsedUrl.Host, Token: segments[len(segments)-1]}
+		if scheme == "gotify+http" {
+			settings.ServerURL = "http://" + parsedUrl.Host
+		}
+		if prefix := segments[:len(segments)-1]; len(prefix) > 0 {
+			settings.ServerURL += "/" + strings.Join(prefix, "/")
+		}
+		return newGotifyNotifier(settings), nil
+	case "telegram":
+		if parsedUrl.User == nil || parsedUrl.Host == "" {
+			return nil, fmt.Errorf("%s: expected telegram://<bot token>@<chat id>", redactNotificationURL(raw))
+		}
+		token := parsedUrl.User.Username()
+		if password := urlPassword(parsedUrl); password != "" {
+			token += ":" + password
+		}
+		return newTelegramNotifier(TelegramSettings{BotToken: token, ChatID: parsedUrl.Host, APIBaseURL: query.Get("api")}), nil
+	}
+	return nil, fmt.Errorf("%s: unknown notification scheme %q", redactNotificationURL(raw), scheme)
+}
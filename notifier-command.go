@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	commandOnMatch        = "match"
+	commandOnRotation     = "rotation"
+	defaultCommandTimeout = 30 * time.Second
+	commandWaitDelay      = time.Second
+	maxCommandOutput      = 4096
+)
+
+type CommandSettings struct {
+	Name           string   `json:"name"`
+	Command        string   `json:"command"`
+	Args           []string `json:"args,omitempty"`
+	On             string   `json:"on"`
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty"`
+}
+
+type commandNotifier struct {
+	settings CommandSettings
+	timeout  time.Duration
+	now      func() time.Time
+}
+
+type commandOutput struct {
+	bytes.Buffer
+}
+
+func (output *commandOutput) Write(data []byte) (int, error) {
+	if remaining := maxCommandOutput - output.Len(); remaining > 0 {
+		if len(data) > remaining {
+			output.Buffer.Write(data[:remaining])
+		} else {
+			output.Buffer.Write(data)
+		}
+	}
+	return len(data), nil
+}
+
+func newCommandNotifier(settings CommandSettings) (*commandNotifier, error) {
+	if settings.Command == "" {
+		return nil, errors.New("no command to run")
+	}
+	if _, err := exec.LookPath(settings.Command); err != nil {
+		return nil, err
+	}
+	if settings.Name == "" {
+		settings.Name = filepath.Base(settings.Command)
+	}
+	switch settings.On {
+	case "":
+		settings.On = commandOnMatch
+	case commandOnMatch, commandOnRotation:
+	default:
+		return nil, fmt.Errorf("unknown event %q, expected %s or %s", settings.On, commandOnMatch, commandOnRotation)
+	}
+	if settings.TimeoutSeconds < 0 {
+		return nil, errors.New("the timeout cannot be negative")
+	}
+	timeout := defaultCommandTimeout
+	if settings.TimeoutSeconds > 0 {
+		timeout = time.Duration(settings.TimeoutSeconds) * time.Second
+	}
+	return &commandNotifier{settings: settings, timeout: timeout, now: time.Now}, nil
+}
+
+func commandEnvironment(data WebhookData, event string) []string {
+	variables := map[string]string{
+		"EVENT":             event,
+		"ACCOUNT":           data.Account,
+		"SKIN":              data.Skin,
+		"UUID":              data.UUID,
+		"LEVEL_ID":          data.LevelId,
+		"PRICE":             strconv.Itoa(data.Price),
+		"REMAINING_SECONDS": strconv.Itoa(data.RemainingSeconds),
+		"VIDEO_URL":         data.VideoURL,
+		"ICON_URL":          data.IconURL,
+		"PRIORITY":          data.Priority,
+		"NIGHT_MARKET":      strconv.FormatBool(data.NightMarket),
+		"RULE":              data.Rule,
+		"TITLE":             data.Title,
+		"MESSAGE":           data.Message,
+	}
+	environment := make([]string, 0, len(variables))
+	for name, value := range variables {
+		environment = append(environment, "SHOPWATCHER_"+name+"="+value)
+	}
+	return environment
+}
+
+func (notifier *commandNotifier) Name() string {
+	return "command:" + notifier.settings.Name
+}
+
+func (notifier *commandNotifier) Accepts(notification Notification) bool {
+	return !notification.Digest && (notification.Summary != nil) == (notifier.settings.On == commandOnRotation)
+}
+
+func (notifier *commandNotifier) Notify(ctx context.Context, notification Notification) error {
+	_, err := notifier.NotifyWithOutput(ctx, notification)
+	return err
+}
+
+func (notifier *commandNotifier) NotifyWithOutput(ctx context.Context, notification Notification) (string, error) {
+	data := newWebhookData(notification, notifier.now())
+	input, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	event := commandOnMatch
+	if notification.Summary != nil {
+		event = commandOnRotation
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifier.timeout)
+	defer cancel()
+	command := exec.CommandContext(ctx, notifier.settings.Command, notifier.settings.Args...)
+	command.Env = append(os.Environ(), commandEnvironment(data, event)...)
+	command.WaitDelay = commandWaitDelay
+	hideCommandWindow(command)
+	command.Stdin = bytes.NewReader(input)
+	var output commandOutput
+	command.Stdout = &output
+	command.Stderr = &output
+	err = command.Run()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		err = nil
+	}
+	text := strings.TrimSpace(output.String())
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return text, fmt.Errorf("command %s: timed out after %s", notifier.settings.Name, notifier.timeout)
+	}
+	if err != nil {
+		return text, fmt.Errorf("command %s: %w", notifier.settings.Name, err)
+	}
+	return text, nil
+}
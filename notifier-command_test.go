@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandEnvironment(t *testing.T) {
+	data := WebhookData{
+		Account:          "player",
+		Skin:             "Prime Vandal",
+		UUID:             "SKIN",
+		LevelId:          "LEVEL",
+		Price:            1775,
+		RemainingSeconds: 90,
+		VideoURL:         "https://example.com/v.mp4",
+		Priority:         "must-have",
+		NightMarket:      true,
+		Rule:             "primes",
+		Title:            appName,
+		Message:          "Prime Vandal is available",
+	}
+	environment := commandEnvironment(data, commandOnMatch)
+	if len(environment) != 14 {
+		t.Errorf("commandEnvironment() has %d variables, want 14: %v", len(environment), environment)
+	}
+	sort.Strings(environment)
+	tests := []string{
+		"SHOPWATCHER_EVENT=match",
+		"SHOPWATCHER_ACCOUNT=player",
+		"SHOPWATCHER_SKIN=Prime Vandal",
+		"SHOPWATCHER_UUID=SKIN",
+		"SHOPWATCHER_LEVEL_ID=LEVEL",
+		"SHOPWATCHER_PRICE=1775",
+		"SHOPWATCHER_REMAINING_SECONDS=90",
+		"SHOPWATCHER_VIDEO_URL=https://example.com/v.mp4",
+		"SHOPWATCHER_ICON_URL=",
+		"SHOPWATCHER_PRIORITY=must-have",
+		"SHOPWATCHER_NIGHT_MARKET=true",
+		"SHOPWATCHER_RULE=primes",
+		"SHOPWATCHER_TITLE=" + appName,
+		"SHOPWATCHER_MESSAGE=Prime Vandal is available",
+	}
+	for _, want := range tests {
+		if index := sort.SearchStrings(environment, want); index == len(environment) || environment[index] != want {
+			t.Errorf("commandEnvironment() = %v, want %q", environment, want)
+		}
+	}
+}
+
+func TestNewCommandNotifier(t *testing.T) {
+	tests := []struct {
+		settings CommandSettings
+		wantName string
+		wantErr  string
+	}{
+		{CommandSettings{Command: "go"}, "command:go", ""},
+		{CommandSettings{Command: "go", Name: "build", On: commandOnRotation}, "command:build", ""},
+		{CommandSettings{}, "", "no command to run"},
+		{CommandSettings{Command: "shopwatcher-missing-command"}, "", "executable file not found"},
+		{CommandSettings{Command: "go", On: "login"}, "", "unknown event"},
+		{CommandSettings{Command: "go", TimeoutSeconds: -1}, "", "cannot be negative"},
+	}
+	for _, test := range tests {
+		notifier, err := newCommandNotifier(test.settings)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("newCommandNotifier(%+v) error = %v, want %q", test.settings, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("newCommandNotifier(%+v) error = %v", test.settings, err)
+			continue
+		}
+		if notifier.Name() != test.wantName || notifier.timeout != defaultCommandTimeout {
+			t.Errorf("newCommandNotifier(%+v) = %s, %v", test.settings, notifier.Name(), notifier.timeout)
+		}
+	}
+}
+
+func TestCommandNotifierNotifyWithOutput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	match := Notification{Title: appName, Account: "player", Match: Match{LevelId: "LEVEL", Skin: Skin{Id: "SKIN", Name: "Prime Vandal"}}}
+	summary := Notification{Title: appName, Account: "player", Summary: &ShopSnapshot{Bundles: []Bundle{{Id: "bundle", Name: "Prime", Price: 7100}}}}
+	tests := []struct {
+		name         string
+		script       string
+		notification Notification
+		timeout      time.Duration
+		wantOutput   string
+		wantErr      string
+	}{
+		{"environment", `printf '%s:%s' "$SHOPWATCHER_EVENT" "$SHOPWATCHER_SKIN"`, match, time.Minute, "match:Prime Vandal", ""},
+		{"standard input", "cat", summary, time.Minute, `"bundles":[{"id":"bundle","name":"Prime","price":7100,"expiresAt":"0001-01-01T00:00:00Z"}]`, ""},
+		{"failure", "echo oops >&2; exit 3", match, time.Minute, "oops", "command test: exit status 3"},
+		{"timeout", "sleep 5", match, 100 * time.Millisecond, "", "command test: timed out after 100ms"},
+		{"background child", "sleep 5 & echo started", match, time.Minute, "started", ""},
+		{"timeout with a background child", "sleep 5 & sleep 5", match, 100 * time.Millisecond, "", "timed out"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notifier, err := newCommandNotifier(CommandSettings{Name: "test", Command: "sh", Args: []string{"-c", test.script}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			notifier.timeout = test.timeout
+			start := time.Now()
+			output, err := notifier.NotifyWithOutput(context.Background(), test.notification)
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("NotifyWithOutput() took %v", elapsed)
+			}
+			if !strings.Contains(output, test.wantOutput) {
+				t.Errorf("NotifyWithOutput() output = %q, want %q", output, test.wantOutput)
+			}
+			if test.wantErr == "" && err != nil || test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("NotifyWithOutput() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommandNotifierInput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	notifier, err := newCommandNotifier(CommandSettings{Command: "sh", Args: []string{"-c", "cat"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := notifier.NotifyWithOutput(context.Background(), Notification{Account: "player", Match: Match{Skin: Skin{Name: "Prime Vandal"}, Priority: PriorityMustHave}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data WebhookData
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		t.Fatalf("standard input %q is not JSON: %v", output, err)
+	}
+	if data.Account != "player" || data.Skin != "Prime Vandal" || data.Priority != string(PriorityMustHave) {
+		t.Errorf("standard input = %+v", data)
+	}
+}
@@ -0,0 +1,144 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusNotificationsName      = "org.freedesktop.Notifications"
+	dbusNotificationsPath      = "/org/freedesktop/Notifications"
+	dbusActionOpenVideo        = "open-video"
+	dbusActionDismissForToday  = "dismiss-for-today"
+	dbusNotificationExpiration = int32(-1)
+)
+
+type dbusNotifier struct {
+	object     dbus.BusObject
+	mutex      sync.Mutex
+	pending    map[uint32]Notification
+	now        func() time.Time
+	openURL    func(url string) error
+	snoozeSkin func(id string, until time.Time) error
+}
+
+func desktopNotifier() (Notifier, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	notifier, err := connectDbusNotifier(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return notifier, nil
+}
+
+func connectDbusNotifier(conn *dbus.Conn) (*dbusNotifier, error) {
+	for _, member := range []string{"ActionInvoked", "NotificationClosed"} {
+		if err := conn.AddMatchSignal(dbus.WithMatchObjectPath(dbusNotificationsPath), dbus.WithMatchInterface(dbusNotificationsName), dbus.WithMatchMember(member)); err != nil {
+			return nil, err
+		}
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	notifier := newDbusNotifier(conn.Object(dbusNotificationsName, dbusNotificationsPath))
+	go notifier.handleSignals(signals)
+	return notifier, nil
+}
+
+func newDbusNotifier(object dbus.BusObject) *dbusNotifier {
+	return &dbusNotifier{
+		object:  object,
+		pending: make(map[uint32]Notification),
+		now:     time.Now,
+		openURL: openURL,
+		snoozeSkin: func(id string, until time.Time) error {
+			return globalStore.Snoozes.SnoozeSkin(id, until)
+		},
+	}
+}
+
+func dbusUrgency(urgency Urgency) byte {
+	switch urgency {
+	case UrgencyHigh:
+		return 2
+	case UrgencyLow:
+		return 0
+	}
+	return 1
+}
+
+func (notifier *dbusNotifier) endOfToday() time.Time {
+	now := notifier.now()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+}
+
+func (notifier *dbusNotifier) Name() string {
+	return "desktop"
+}
+
+func (notifier *dbusNotifier) Notify(ctx context.Context, notification Notification) error {
+	var actions []string
+	if notification.Match.Skin.Video != "" {
+		actions = append(actions, dbusActionOpenVideo, tr("Open video"))
+	}
+	if notification.Match.Skin.Id != "" {
+		actions = append(actions, dbusActionDismissForToday, tr("Dismiss for today"))
+	}
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(dbusUrgency(notification.Match.Priority.Urgency())),
+		"desktop-entry": dbus.MakeVariant("valorant-shopwatcher"),
+	}
+	var id uint32
+	call := notifier.object.CallWithContext(ctx, dbusNotificationsName+".Notify", 0, appName, uint32(0), "", notification.Title, notification.Message, actions, hints, dbusNotificationExpiration)
+	if err := call.Store(&id); err != nil {
+		return err
+	}
+	notifier.mutex.Lock()
+	notifier.pending[id] = notification
+	notifier.mutex.Unlock()
+	return nil
+}
+
+func (notifier *dbusNotifier) handleSignals(signals <-chan *dbus.Signal) {
+	for signal := range signals {
+		notifier.handleSignal(signal)
+	}
+}
+
+func (notifier *dbusNotifier) handleSignal(signal *dbus.Signal) {
+	if len(signal.Body) < 2 {
+		return
+	}
+	id, ok := signal.Body[0].(uint32)
+	if !ok {
+		return
+	}
+	notifier.mutex.Lock()
+	notification, ok := notifier.pending[id]
+	if signal.Name == dbusNotificationsName+".NotificationClosed" {
+		delete(notifier.pending, id)
+	}
+	notifier.mutex.Unlock()
+	if !ok || signal.Name != dbusNotificationsName+".ActionInvoked" {
+		return
+	}
+	action, _ := signal.Body[1].(string)
+	switch action {
+	case dbusActionOpenVideo:
+		if err := notifier.openURL(notification.Match.Skin.Video); err != nil {
+			showError(tr("The app could not open the video"), err)
+		}
+	case dbusActionDismissForToday:
+		if err := notifier.snoozeSkin(notification.Match.Skin.Id, notifier.endOfToday()); err != nil {
+			showError(tr("The app could not save the data"), err)
+		}
+	}
+}
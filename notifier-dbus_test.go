@@ -0,0 +1,168 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type fakeNotificationServer struct {
+	mutex   sync.Mutex
+	nextId  uint32
+	actions [][]string
+	urgency []byte
+}
+
+func (server *fakeNotificationServer) Notify(appName string, replacesId uint32, icon string, summary string, body string, actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.nextId++
+	server.actions = append(server.actions, actions)
+	urgency, _ := hints["urgency"].Value().(byte)
+	server.urgency = append(server.urgency, urgency)
+	return server.nextId, nil
+}
+
+func startSessionBus(t *testing.T) string {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+	cmd := exec.Command(daemon, "--session", "--print-address", "--nofork")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("dbus-daemon could not start: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func connectSessionBus(t *testing.T, address string) *dbus.Conn {
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestDbusNotifierOnSessionBus(t *testing.T) {
+	address := startSessionBus(t)
+	serverConn := connectSessionBus(t, address)
+	server := &fakeNotificationServer{}
+	if err := serverConn.Export(server, dbusNotificationsPath, dbusNotificationsName); err != nil {
+		t.Fatal(err)
+	}
+	if reply, err := serverConn.RequestName(dbusNotificationsName, dbus.NameFlagDoNotQueue); err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName() = %v, %v", reply, err)
+	}
+
+	notifier, err := connectDbusNotifier(connectSessionBus(t, address))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC)
+	snoozed := make(chan string, 1)
+	notifier.now = func() time.Time { return now }
+	notifier.snoozeSkin = func(id string, until time.Time) error {
+		if want := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC); !until.Equal(want) {
+			t.Errorf("snoozeSkin() until = %v, want %v", until, want)
+		}
+		snoozed <- id
+		return nil
+	}
+
+	notification := Notification{Title: "Prime Vandal", Match: Match{Skin: Skin{Id: "skin-1", Video: "https://example.com/video.mp4"}, Priority: PriorityMustHave}}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() = %v", err)
+	}
+	server.mutex.Lock()
+	actions, urgency := server.actions[0], server.urgency[0]
+	server.mutex.Unlock()
+	if len(actions) != 4 || actions[0] != dbusActionOpenVideo || actions[2] != dbusActionDismissForToday {
+		t.Errorf("Notify() actions = %v", actions)
+	}
+	if urgency != 2 {
+		t.Errorf("Notify() urgency = %d, want 2", urgency)
+	}
+
+	if err := serverConn.Emit(dbusNotificationsPath, dbusNotificationsName+".ActionInvoked", uint32(1), dbusActionDismissForToday); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case id := <-snoozed:
+		if id != "skin-1" {
+			t.Errorf("snoozeSkin() id = %q, want skin-1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dismiss action was not handled")
+	}
+}
+
+func TestDbusNotifierHandleSignal(t *testing.T) {
+	tests := []struct {
+		name    string
+		signal  *dbus.Signal
+		opened  string
+		snoozed string
+	}{
+		{"open video", &dbus.Signal{Name: dbusNotificationsName + ".ActionInvoked", Body: []interface{}{uint32(7), dbusActionOpenVideo}}, "https://example.com/video.mp4", ""},
+		{"dismiss for today", &dbus.Signal{Name: dbusNotificationsName + ".ActionInvoked", Body: []interface{}{uint32(7), dbusActionDismissForToday}}, "", "skin-1"},
+		{"unknown notification", &dbus.Signal{Name: dbusNotificationsName + ".ActionInvoked", Body: []interface{}{uint32(8), dbusActionOpenVideo}}, "", ""},
+		{"closed", &dbus.Signal{Name: dbusNotificationsName + ".NotificationClosed", Body: []interface{}{uint32(7), uint32(2)}}, "", ""},
+		{"malformed", &dbus.Signal{Name: dbusNotificationsName + ".ActionInvoked", Body: []interface{}{"7"}}, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opened, snoozed string
+			notifier := newDbusNotifier(nil)
+			notifier.openURL = func(url string) error {
+				opened = url
+				return nil
+			}
+			notifier.snoozeSkin = func(id string, until time.Time) error {
+				snoozed = id
+				return nil
+			}
+			notifier.pending[7] = Notification{Match: Match{Skin: Skin{Id: "skin-1", Video: "https://example.com/video.mp4"}}}
+			notifier.handleSignal(test.signal)
+			if opened != test.opened || snoozed != test.snoozed {
+				t.Errorf("handleSignal() opened %q and snoozed %q, want %q and %q", opened, snoozed, test.opened, test.snoozed)
+			}
+		})
+	}
+}
+
+func TestDbusUrgency(t *testing.T) {
+	tests := []struct {
+		urgency Urgency
+		want    byte
+	}{
+		{UrgencyLow, 0},
+		{UrgencyNormal, 1},
+		{UrgencyHigh, 2},
+	}
+	for _, test := range tests {
+		if got := dbusUrgency(test.urgency); got != test.want {
+			t.Errorf("dbusUrgency(%d) = %d, want %d", test.urgency, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	discordColorHigh   = 0xFF4655
+	discordColorNormal = 0x3BA55D
+	discordColorLow    = 0x99AAB5
+)
+
+type discordWebhookMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Thumbnail   *discordEmbedImage  `json:"thumbnail,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbedImage struct {
+	URL string `json:"url"`
+}
+
+type discordNotifier struct {
+	settings DiscordSettings
+	now      func() time.Time
+}
+
+func newDiscordNotifier(settings DiscordSettings) *discordNotifier {
+	return &discordNotifier{settings: settings, now: time.Now}
+}
+
+func (notifier *discordNotifier) webhookURL(account string) string {
+	if url, ok := notifier.settings.AccountWebhookURLs[account]; ok {
+		return url
+	}
+	return notifier.settings.WebhookURL
+}
+
+func discordColor(urgency Urgency) int {
+	switch urgency {
+	case UrgencyHigh:
+		return discordColorHigh
+	case UrgencyLow:
+		return discordColorLow
+	}
+	return discordColorNormal
+}
+
+func (notifier *discordNotifier) embed(notification Notification) discordEmbed {
+	if notification.Summary != nil {
+		return notifier.summaryEmbed(notification)
+	}
+	match := notification.Match
+	embed := discordEmbed{
+		Title:       localizedName(match.Skin, locale),
+		Description: notification.Message,
+		URL:         match.Skin.Video,
+		Color:       discordColor(match.Priority.Urgency()),
+		Timestamp:   notifier.now().UTC().Format(time.RFC3339),
+	}
+	if match.LevelId != "" {
+		embed.Thumbnail = &discordEmbedImage{URL: skinIconURL(match.LevelId)}
+	}
+	if match.Skin.Price > 0 {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Price"), Value: fmt.Sprintf("%d VP", match.Skin.Price), Inline: true})
+	}
+	embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Priority"), Value: match.Priority.Label(), Inline: true})
+	if notification.Account != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Account"), Value: notification.Account, Inline: true})
+	}
+	if !match.ExpiresAt.IsZero() {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Time left"), Value: fmt.Sprintf("<t:%d:R>", match.ExpiresAt.Unix()), Inline: true})
+	}
+	if match.Rule != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Rule"), Value: match.Rule, Inline: true})
+	}
+	if match.Skin.Video != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Video"), Value: match.Skin.Video})
+	}
+	return embed
+}
+
+func (notifier *discordNotifier) summaryEmbed(notification Notification) discordEmbed {
+	embed := discordEmbed{
+		Title:       notification.Title,
+		Description: notification.Message,
+		Color:       discordColorNormal,
+		Timestamp:   notifier.now().UTC().Format(time.RFC3339),
+	}
+	if notification.Account != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Account"), Value: notification.Account, Inline: true})
+	}
+	for _, offer := range notification.Summary.Offers {
+		if !offer.NightMarket && !offer.ExpiresAt.IsZero() {
+			embed.Fields = append(embed.Fields, discordEmbedField{Name: tr("Time left"), Value: fmt.Sprintf("<t:%d:R>", offer.ExpiresAt.Unix()), Inline: true})
+			break
+		}
+	}
+	return embed
+}
+
+func (notifier *discordNotifier) Name() string {
+	_, path, ok := strings.Cut(notifier.settings.WebhookURL, "/webhooks/")
+	if !ok {
+		return "discord"
+	}
+	id, _, _ := strings.Cut(path, "/")
+	return "discord:" + id
+}
+
+func (notifier *discordNotifier) Accepts(notification Notification) bool {
+	return notifier.webhookURL(notification.Account) != "" && !notification.Digest && (notification.Summary == nil || globalStore.Settings.Notifications.RotationSummary)
+}
+
+func (notifier *discordNotifier) Notify(ctx context.Context, notification Notification) error {
+	url := notifier.webhookURL(notification.Account)
+	if url == "" {
+		return fmt.Errorf("discord: no webhook is set for %s", notification.Account)
+	}
+	message := discordWebhookMessage{Username: appName, Embeds: []discordEmbed{notifier.embed(notification)}}
+	if err := postJSON(ctx, url, message, nil); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDiscordNotifierNotify(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		account      string
+		status       int
+		wantPath     string
+		wantRequests int
+		wantErr      bool
+	}{
+		{"default webhook", "player", http.StatusNoContent, "/api/webhooks/1/default", 1, false},
+		{"account webhook", "smurf", http.StatusNoContent, "/api/webhooks/2/smurf", 1, false},
+		{"failure is not retried", "player", http.StatusInternalServerError, "/api/webhooks/1/default", 1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int
+			var message discordWebhookMessage
+			server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != test.wantPath {
+					t.Errorf("posted to %s, want %s", r.URL.Path, test.wantPath)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(test.status)
+			})
+			notifier := newDiscordNotifier(DiscordSettings{
+				WebhookURL:         server.URL + "/api/webhooks/1/default",
+				AccountWebhookURLs: map[string]string{"smurf": server.URL + "/api/webhooks/2/smurf"},
+			})
+			notifier.now = func() time.Time { return now }
+			notification := Notification{
+				Title:   appName,
+				Message: "Prime Vandal is available",
+				Account: test.account,
+				Match:   Match{LevelId: "ABC", Skin: Skin{Name: "Prime Vandal", Price: 1775, Video: "https://example.com/v.mp4"}, Priority: PriorityMustHave, ExpiresAt: now.Add(time.Hour)},
+			}
+			err := notifier.Notify(context.Background(), notification)
+			if test.wantErr {
+				var httpError *HTTPError
+				if !errors.As(err, &httpError) || httpError.StatusCode != test.status {
+					t.Errorf("Notify() error = %v, want HTTP %d", err, test.status)
+				}
+			} else if err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if requests != test.wantRequests {
+				t.Errorf("Notify() made %d requests, want %d", requests, test.wantRequests)
+			}
+			if len(message.Embeds) != 1 {
+				t.Fatalf("message = %+v", message)
+			}
+			embed := message.Embeds[0]
+			if embed.Title != "Prime Vandal" || embed.Color != discordColorHigh || embed.URL != "https://example.com/v.mp4" || embed.Thumbnail == nil || embed.Timestamp != "2026-10-19T12:00:00Z" {
+				t.Errorf("embed = %+v", embed)
+			}
+			fields := make(map[string]string)
+			for _, field := range embed.Fields {
+				fields[field.Name] = field.Value
+			}
+			if fields["Price"] != "1775 VP" || fields["Account"] != test.account || fields["Time left"] == "" {
+				t.Errorf("embed fields = %v", fields)
+			}
+		})
+	}
+}
+
+func TestDiscordNotifierWithoutWebhook(t *testing.T) {
+	useTestOutbox(t)
+	notifier := newDiscordNotifier(DiscordSettings{AccountWebhookURLs: map[string]string{"smurf": "https://discord.com/api/webhooks/2/smurf"}})
+	tests := []struct {
+		account string
+		want    bool
+	}{
+		{"smurf", true},
+		{"player", false},
+	}
+	for _, test := range tests {
+		notification := Notification{Account: test.account, Match: Match{LevelId: "ABC"}}
+		if got := notifier.Accepts(notification); got != test.want {
+			t.Errorf("Accepts(%q) = %v, want %v", test.account, got, test.want)
+		}
+	}
+	if err := notifier.Notify(context.Background(), Notification{Account: "player"}); err == nil {
+		t.Error("Notify() without a webhook did not fail")
+	}
+}
+
+func TestDiscordNotifierName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://discord.com/api/webhooks/123/token", "discord:123"},
+		{"", "discord"},
+	}
+	for _, test := range tests {
+		if got := newDiscordNotifier(DiscordSettings{WebhookURL: test.url}).Name(); got != test.want {
+			t.Errorf("Name(%q) = %q, want %q", test.url, got, test.want)
+		}
+	}
+}
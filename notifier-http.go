@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxHTTPErrorBody = 512
+
+var notifierClient = &http.Client{Transport: newTransport(http.ProxyFromEnvironment, nil), Timeout: time.Minute}
+
+type HTTPError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+func setupNotifierTransport(network NetworkSettings) error {
+	proxy, err := parseProxy(network.Proxy)
+	if err != nil {
+		return fmt.Errorf("proxy: %w", err)
+	}
+	rootCAs, err := loadRootCAs(network.CACertFiles)
+	if err != nil {
+		return fmt.Errorf("CA certificates: %w", err)
+	}
+	notifierClient.Transport = newTransport(proxy, &tls.Config{RootCAs: rootCAs})
+	return nil
+}
+
+func sendNotifierRequest(ctx context.Context, req *http.Request, out any) error {
+	res, err := notifierClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return redactURLError(err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body := strings.TrimSpace(string(data))
+		if len(body) > maxHTTPErrorBody {
+			body = body[:maxHTTPErrorBody] + "..."
+		}
+		return &HTTPError{StatusCode: res.StatusCode, Body: body, RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return &DecodeError{Host: req.URL.Host, Err: err}
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	return sendNotifierRequest(ctx, req, nil)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubNotifierServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	previous := notifierClient
+	notifierClient = server.Client()
+	t.Cleanup(func() {
+		notifierClient = previous
+		server.Close()
+	})
+	return server
+}
+
+func TestPostJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		body       string
+		wantErr    *HTTPError
+	}{
+		{"success", http.StatusNoContent, "", "", nil},
+		{"client error", http.StatusBadRequest, "", `{"message": "Invalid Form Body"}`, &HTTPError{StatusCode: 400, Body: `{"message": "Invalid Form Body"}`}},
+		{"rate limited", http.StatusTooManyRequests, "3", "", &HTTPError{StatusCode: 429, RetryAfter: 3 * time.Second}},
+		{"long body", http.StatusBadGateway, "", strings.Repeat("x", 600), &HTTPError{StatusCode: 502, Body: strings.Repeat("x", maxHTTPErrorBody) + "..."}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attempts := 0
+			server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Test") != "yes" {
+					t.Errorf("request = %s %v", r.Method, r.Header)
+				}
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			})
+			err := postJSON(context.Background(), server.URL, map[string]string{"a": "b"}, map[string]string{"X-Test": "yes"})
+			if attempts != 1 {
+				t.Errorf("postJSON() made %d attempts, want 1", attempts)
+			}
+			if test.wantErr == nil {
+				if err != nil {
+					t.Errorf("postJSON() error = %v", err)
+				}
+				return
+			}
+			var httpError *HTTPError
+			if !errors.As(err, &httpError) || *httpError != *test.wantErr {
+				t.Errorf("postJSON() error = %#v, want %#v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendNotifierRequestDecodesResponses(t *testing.T) {
+	server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Query().Get("body")))
+	})
+	var out struct{ Ok bool }
+	req, _ := http.NewRequest("GET", server.URL+"?body=%7B%22ok%22%3Atrue%7D", nil)
+	if err := sendNotifierRequest(context.Background(), req, &out); err != nil || !out.Ok {
+		t.Errorf("sendNotifierRequest() = %v, %+v", err, out)
+	}
+	req, _ = http.NewRequest("GET", server.URL+"?body=oops", nil)
+	var decodeError *DecodeError
+	if err := sendNotifierRequest(context.Background(), req, &out); !errors.As(err, &decodeError) {
+		t.Errorf("sendNotifierRequest() error = %v, want a decode error", err)
+	}
+}
+
+func TestSetupNotifierTransport(t *testing.T) {
+	previous := notifierClient.Transport
+	t.Cleanup(func() { notifierClient.Transport = previous })
+	if err := setupNotifierTransport(NetworkSettings{Proxy: "ftp://proxy"}); err == nil {
+		t.Error("setupNotifierTransport() accepted an ftp proxy")
+	}
+	if err := setupNotifierTransport(NetworkSettings{Proxy: "direct", TLSProfile: "tls13-only"}); err != nil {
+		t.Fatal(err)
+	}
+	transport := notifierClient.Transport.(*http.Transport)
+	if transport.Proxy != nil || transport.TLSClientConfig.MinVersion != 0 {
+		t.Errorf("notifier transport = %+v, want no proxy and no Riot TLS profile", transport)
+	}
+	if notifierClient.Jar != nil {
+		t.Error("the notifier client shares a cookie jar")
+	}
+}
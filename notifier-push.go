@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const defaultNtfyServerURL = "https://ntfy.sh"
+
+var defaultNtfyPriorities = map[Priority]int{PriorityMustHave: 5, PriorityNiceToHave: 3, PriorityWatching: 2}
+
+var defaultGotifyPriorities = map[Priority]int{PriorityMustHave: 8, PriorityNiceToHave: 5, PriorityWatching: 2}
+
+type NtfySettings struct {
+	ServerURL  string           `json:"serverUrl,omitempty"`
+	Topic      string           `json:"topic"`
+	Token      string           `json:"token,omitempty"`
+	Tags       []string         `json:"tags,omitempty"`
+	Priorities map[Priority]int `json:"priorities,omitempty"`
+}
+
+type GotifySettings struct {
+	ServerURL  string           `json:"serverUrl"`
+	Token      string           `json:"token"`
+	Priorities map[Priority]int `json:"priorities,omitempty"`
+}
+
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+	Icon     string   `json:"icon,omitempty"`
+}
+
+type gotifyMessage struct {
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"`
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+type ntfyNotifier struct {
+	settings NtfySettings
+}
+
+type gotifyNotifier struct {
+	settings GotifySettings
+}
+
+func pushPriority(priority Priority, custom map[Priority]int, defaults map[Priority]int) int {
+	if value, ok := custom[priority.OrDefault()]; ok {
+		return value
+	}
+	return defaults[priority.OrDefault()]
+}
+
+func newNtfyNotifier(settings NtfySettings) *ntfyNotifier {
+	if settings.ServerURL == "" {
+		settings.ServerURL = defaultNtfyServerURL
+	}
+	settings.ServerURL = strings.TrimSuffix(settings.ServerURL, "/")
+	return &ntfyNotifier{settings: settings}
+}
+
+func (notifier *ntfyNotifier) Name() string {
+	return "ntfy:" + notifier.settings.ServerURL + "/" + notifier.settings.Topic
+}
+
+func (notifier *ntfyNotifier) Notify(ctx context.Context, notification Notification) error {
+	message := ntfyMessage{
+		Topic:    notifier.settings.Topic,
+		Title:    notification.Title,
+		Message:  notification.Message,
+		Priority: pushPriority(notification.Match.Priority, notifier.settings.Priorities, defaultNtfyPriorities),
+		Tags:     notifier.settings.Tags,
+		Click:    notification.Match.Skin.Video,
+	}
+	if notification.Match.LevelId != "" {
+		message.Icon = skinIconURL(notification.Match.LevelId)
+	}
+	headers := make(map[string]string)
+	if notifier.settings.Token != "" {
+		headers["Authorization"] = "Bearer " + notifier.settings.Token
+	}
+	if err := postJSON(ctx, notifier.settings.ServerURL, message, headers); err != nil {
+		return fmt.Errorf("ntfy: %w", err)
+	}
+	return nil
+}
+
+func newGotifyNotifier(settings GotifySettings) *gotifyNotifier {
+	settings.ServerURL = strings.TrimSuffix(settings.ServerURL, "/")
+	return &gotifyNotifier{settings: settings}
+}
+
+func (notifier *gotifyNotifier) Name() string {
+	return "gotify:" + notifier.settings.ServerURL
+}
+
+func (notifier *gotifyNotifier) Notify(ctx context.Context, notification Notification) error {
+	message := gotifyMessage{
+		Title:    notification.Title,
+		Message:  notification.Message,
+		Priority: pushPriority(notification.Match.Priority, notifier.settings.Priorities, defaultGotifyPriorities),
+	}
+	if video := notification.Match.Skin.Video; video != "" {
+		message.Extras = map[string]interface{}{
+			"client::notification": map[string]interface{}{"click": map[string]string{"url": video}},
+		}
+	}
+	headers := map[string]string{"X-Gotify-Key": notifier.settings.Token}
+	if err := postJSON(ctx, notifier.settings.ServerURL+"/message", message, headers); err != nil {
+		return fmt.Errorf("gotify: %w", err)
+	}
+	return nil
+}
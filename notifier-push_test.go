@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNtfyNotifierNotify(t *testing.T) {
+	tests := []struct {
+		name         string
+		settings     NtfySettings
+		priority     Priority
+		wantAuth     string
+		wantPriority int
+	}{
+		{"default priorities", NtfySettings{Topic: "valorant", Tags: []string{"video_game"}}, PriorityMustHave, "", 5},
+		{"custom priorities and token", NtfySettings{Topic: "valorant", Token: "tk_secret", Priorities: map[Priority]int{PriorityWatching: 1}}, PriorityWatching, "Bearer tk_secret", 1},
+		{"default priority for unset", NtfySettings{Topic: "valorant"}, "", "", 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var message ntfyMessage
+			server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/" || r.Header.Get("Authorization") != test.wantAuth {
+					t.Errorf("request = %s %v", r.URL.Path, r.Header)
+				}
+				json.NewDecoder(r.Body).Decode(&message)
+			})
+			test.settings.ServerURL = server.URL + "/"
+			notifier := newNtfyNotifier(test.settings)
+			notification := Notification{Title: appName, Message: "Prime Vandal", Match: Match{LevelId: "LEVEL", Skin: Skin{Video: "https://example.com/v.mp4"}, Priority: test.priority}}
+			if err := notifier.Notify(context.Background(), notification); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if message.Topic != "valorant" || message.Title != appName || message.Message != "Prime Vandal" || message.Priority != test.wantPriority || message.Click != "https://example.com/v.mp4" || message.Icon == "" {
+				t.Errorf("message = %+v", message)
+			}
+			if len(message.Tags) != len(test.settings.Tags) {
+				t.Errorf("tags = %v, want %v", message.Tags, test.settings.Tags)
+			}
+		})
+	}
+}
+
+func TestGotifyNotifierNotify(t *testing.T) {
+	tests := []struct {
+		name         string
+		priority     Priority
+		priorities   map[Priority]int
+		video        string
+		wantPriority int
+	}{
+		{"must-have with video", PriorityMustHave, nil, "https://example.com/v.mp4", 8},
+		{"custom priority without video", PriorityNiceToHave, map[Priority]int{PriorityNiceToHave: 4}, "", 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var message gotifyMessage
+			server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/message" || r.Header.Get("X-Gotify-Key") != "app-token" {
+					t.Errorf("request = %s %v", r.URL.Path, r.Header)
+				}
+				json.NewDecoder(r.Body).Decode(&message)
+			})
+			notifier := newGotifyNotifier(GotifySettings{ServerURL: server.URL + "/", Token: "app-token", Priorities: test.priorities})
+			notification := Notification{Title: appName, Message: "Prime Vandal", Match: Match{Skin: Skin{Video: test.video}, Priority: test.priority}}
+			if err := notifier.Notify(context.Background(), notification); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if message.Priority != test.wantPriority || message.Title != appName {
+				t.Errorf("message = %+v", message)
+			}
+			if (message.Extras != nil) != (test.video != "") {
+				t.Errorf("extras = %v", message.Extras)
+			}
+		})
+	}
+}
+
+func TestPushNotifierNames(t *testing.T) {
+	if got := newNtfyNotifier(NtfySettings{Topic: "valorant"}).Name(); got != "ntfy:https://ntfy.sh/valorant" {
+		t.Errorf("ntfy Name() = %q", got)
+	}
+	if got := newGotifyNotifier(GotifySettings{ServerURL: "https://push.example.com/"}).Name(); got != "gotify:https://push.example.com" {
+		t.Errorf("gotify Name() = %q", got)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	smtpSecurityStartTLS = "starttls"
+	smtpSecurityTLS      = "tls"
+	smtpSecurityNone     = "none"
+	emailModeInstant     = "instant"
+	emailModeDigest      = "digest"
+)
+
+var instantMailTemplate = template.Must(template.New("instant").Parse(`<html><body style="font-family: sans-serif">
+<h2>{{.Name}}</h2>
+<p>{{.Message}}</p>
+{{if .Icon}}<p><img src="{{.Icon}}" alt="{{.Name}}" height="80"></p>{{end}}
+{{if .Video}}<p><a href="{{.Video}}">{{.VideoLabel}}</a></p>{{end}}
+</body></html>`))
+
+var digestMailTemplate = template.Must(template.New("digest").Parse(`<html><body style="font-family: sans-serif">
+<h2>{{.Title}}</h2>
+<table cellpadding="6" style="border-collapse: collapse">
+<tr><th align="left">{{.SkinLabel}}</th><th align="right">{{.PriceLabel}}</th><th align="left">{{.WishlistLabel}}</th></tr>
+{{range .Rows}}<tr{{if .Hit}} style="background-color: #ffe3e5; font-weight: bold"{{end}}>
+<td>{{if .Video}}<a href="{{.Video}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{if .NightMarket}} ({{$.NightMarketLabel}}){{end}}</td>
+<td align="right">{{if .Price}}{{.Price}} VP{{end}}</td>
+<td>{{.Priority}}</td>
+</tr>
+{{end}}</table>
+</body></html>`))
+
+type SMTPSettings struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Security string   `json:"security"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Mode     string   `json:"mode"`
+}
+
+type digestRow struct {
+	Name        string
+	Price       int
+	NightMarket bool
+	Video       string
+	Hit         bool
+	Priority    string
+}
+
+type smtpNotifier struct {
+	settings  SMTPSettings
+	tlsConfig *tls.Config
+	now       func() time.Time
+}
+
+func newSmtpNotifier(settings SMTPSettings, rootCAs *x509.CertPool) (*smtpNotifier, error) {
+	if settings.Security == "" {
+		settings.Security = smtpSecurityStartTLS
+	}
+	if settings.Mode == "" {
+		settings.Mode = emailModeInstant
+	}
+	if settings.Port == 0 {
+		settings.Port = 587
+		if settings.Security == smtpSecurityTLS {
+			settings.Port = 465
+		}
+	}
+	switch {
+	case settings.Host == "":
+		return nil, errors.New("missing SMTP host")
+	case settings.From == "":
+		return nil, errors.New("missing sender address")
+	case len(settings.To) == 0:
+		return nil, errors.New("missing recipient address")
+	case settings.Security != smtpSecurityStartTLS && settings.Security != smtpSecurityTLS && settings.Security != smtpSecurityNone:
+		return nil, fmt.Errorf("unknown security %q, expected starttls, tls or none", settings.Security)
+	case settings.Mode != emailModeInstant && settings.Mode != emailModeDigest:
+		return nil, fmt.Errorf("unknown mode %q, expected instant or digest", settings.Mode)
+	}
+	return &smtpNotifier{
+		settings:  settings,
+		tlsConfig: &tls.Config{ServerName: settings.Host, RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+		now:       time.Now,
+	}, nil
+}
+
+func (notifier *smtpNotifier) Name() string {
+	return "email:" + notifier.settings.Host + ":" + strings.Join(notifier.settings.To, ",")
+}
+
+func (notifier *smtpNotifier) Accepts(notification Notification) bool {
+	if notifier.settings.Mode == emailModeDigest {
+		return notification.Digest
+	}
+	return !notification.Digest && (notification.Summary == nil || globalStore.Settings.Notifications.RotationSummary)
+}
+
+func (notifier *smtpNotifier) Notify(ctx context.Context, notification Notification) error {
+	if notification.Summary != nil {
+		subject, body, err := notifier.digest(*notification.Summary)
+		if err != nil {
+			return err
+		}
+		return notifier.send(ctx, subject, body)
+	}
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":       localizedName(notification.Match.Skin, locale),
+		"Message":    notification.Message,
+		"Video":      notification.Match.Skin.Video,
+		"VideoLabel": tr("Watch the video"),
+	}
+	if notification.Match.LevelId != "" {
+		data["Icon"] = skinIconURL(notification.Match.LevelId)
+	}
+	if err := instantMailTemplate.Execute(&body, data); err != nil {
+		return err
+	}
+	return notifier.send(ctx, notification.Message, body.String())
+}
+
+func (notifier *smtpNotifier) digest(snapshot ShopSnapshot) (string, string, error) {
+	hits := make(map[string]Match)
+	for _, match := range snapshot.Matches {
+		hits[match.LevelId] = match
+	}
+	var rows []digestRow
+	for _, offer := range snapshot.Offers {
+		row := digestRow{Name: localizedName(offer.Skin, locale), Price: offer.Price, NightMarket: offer.NightMarket, Video: offer.Skin.Video}
+		if match, ok := hits[offer.LevelId]; ok {
+			row.Hit = true
+			row.Priority = match.Priority.Label()
+		}
+		rows = append(rows, row)
+	}
+	for _, bundle := range snapshot.Bundles {
+		rows = append(rows, digestRow{Name: tr("Bundle: %s", bundle.Name), Price: bundle.Price})
+	}
+	title := tr("Your Valorant shop of %s", notifier.now().Format("2006-01-02"))
+	if snapshot.Account != "" {
+		title += " (" + snapshot.Account + ")"
+	}
+	var body bytes.Buffer
+	err := digestMailTemplate.Execute(&body, map[string]interface{}{
+		"Title":            title,
+		"SkinLabel":        tr("Skin"),
+		"PriceLabel":       tr("Price"),
+		"WishlistLabel":    tr("Wishlist"),
+		"NightMarketLabel": tr("night market"),
+		"Rows":             rows,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	subject := title
+	if len(snapshot.Matches) > 0 {
+		subject = tr("%d wishlist skins in your Valorant shop", len(snapshot.Matches))
+	}
+	return subject, body.String(), nil
+}
+
+func (notifier *smtpNotifier) message(subject string, html string) ([]byte, error) {
+	var message bytes.Buffer
+	headers := [][2]string{
+		{"From", notifier.settings.From},
+		{"To", strings.Join(notifier.settings.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", notifier.now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/html; charset=utf-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		message.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	message.WriteString("\r\n")
+	writer := quotedprintable.NewWriter(&message)
+	if _, err := writer.Write([]byte(html)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
+
+func (notifier *smtpNotifier) send(ctx context.Context, subject string, html string) error {
+	message, err := notifier.message(subject, html)
+	if err != nil {
+		return err
+	}
+	if err := notifier.deliver(ctx, message); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+func (notifier *smtpNotifier) deliver(ctx context.Context, message []byte) error {
+	settings := notifier.settings
+	address := net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port))
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(requestTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+	if settings.Security == smtpSecurityTLS {
+		conn = tls.Client(conn, notifier.tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, settings.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if settings.Security == smtpSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("the server does not support STARTTLS")
+		}
+		if err := client.StartTLS(notifier.tlsConfig); err != nil {
+			return err
+		}
+	}
+	if settings.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", settings.Username, settings.Password, settings.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(settings.From); err != nil {
+		return err
+	}
+	for _, recipient := range settings.To {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
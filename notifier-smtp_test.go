@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeSMTPServer struct {
+	listener net.Listener
+	config   *tls.Config
+	implicit bool
+	startTLS bool
+	password string
+	received chan fakeSMTPSession
+}
+
+type fakeSMTPSession struct {
+	TLS        bool
+	User       string
+	From       string
+	Recipients []string
+	Data       string
+}
+
+func newTestCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func startFakeSMTPServer(t *testing.T, certificate tls.Certificate, implicit bool, startTLS bool, password string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTPServer{
+		listener: listener,
+		config:   &tls.Config{Certificates: []tls.Certificate{certificate}},
+		implicit: implicit,
+		startTLS: startTLS,
+		password: password,
+		received: make(chan fakeSMTPSession, 1),
+	}
+	if implicit {
+		server.listener = tls.NewListener(listener, server.config)
+	}
+	t.Cleanup(func() { server.listener.Close() })
+	go server.serve()
+	return server
+}
+
+func (server *fakeSMTPServer) port() int {
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (server *fakeSMTPServer) serve() {
+	conn, err := server.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	session := fakeSMTPSession{TLS: server.implicit}
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 fake ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			conn.Write([]byte("250-fake\r\n"))
+			if server.startTLS && !session.TLS {
+				conn.Write([]byte("250-STARTTLS\r\n"))
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, server.config)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+			session.TLS = true
+		case "AUTH":
+			fields := strings.Fields(command)
+			credentials, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			parts := strings.Split(string(credentials), "\x00")
+			if len(parts) != 3 || parts[2] != server.password {
+				reply("535 authentication failed")
+				continue
+			}
+			session.User = parts[1]
+			reply("235 ok")
+		case "MAIL":
+			session.From = strings.TrimSuffix(strings.TrimPrefix(command[len("MAIL FROM:"):], "<"), ">")
+			reply("250 ok")
+		case "RCPT":
+			session.Recipients = append(session.Recipients, strings.TrimSuffix(strings.TrimPrefix(command[len("RCPT TO:"):], "<"), ">"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			session.Data = data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			server.received <- session
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSmtpNotifierSend(t *testing.T) {
+	certificate, pool := newTestCertificate(t)
+	tests := []struct {
+		name      string
+		security  string
+		startTLS  bool
+		username  string
+		password  string
+		wantTLS   bool
+		wantError string
+	}{
+		{"starttls with auth", smtpSecurityStartTLS, true, "bot", "secret", true, ""},
+		{"implicit tls with auth", smtpSecurityTLS, false, "bot", "secret", true, ""},
+		{"plain without auth", smtpSecurityNone, false, "", "", false, ""},
+		{"starttls unsupported", smtpSecurityStartTLS, false, "", "", false, "does not support STARTTLS"},
+		{"wrong password", smtpSecurityStartTLS, true, "bot", "wrong", true, "535"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := startFakeSMTPServer(t, certificate, test.security == smtpSecurityTLS, test.startTLS, "secret")
+			notifier, err := newSmtpNotifier(SMTPSettings{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				Security: test.security,
+				Username: test.username,
+				Password: test.password,
+				From:     "shop@example.com",
+				To:       []string{"a@example.com", "b@example.com"},
+			}, pool)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = notifier.send(context.Background(), "Prime Vandal is available", "<p>Prime Vandal</p>")
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Errorf("send() error = %v, want %q", err, test.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("send() error = %v", err)
+			}
+			select {
+			case session := <-server.received:
+				if session.TLS != test.wantTLS || session.User != test.username || session.From != "shop@example.com" || strings.Join(session.Recipients, ",") != "a@example.com,b@example.com" {
+					t.Errorf("session = %+v", session)
+				}
+				if !strings.Contains(session.Data, "Subject: Prime Vandal is available") || !strings.Contains(session.Data, "<p>Prime Vandal</p>") {
+					t.Errorf("message = %q", session.Data)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the server did not receive the message")
+			}
+		})
+	}
+}
+
+func TestSmtpNotifierAccepts(t *testing.T) {
+	match := Notification{Match: Match{LevelId: "a"}}
+	summary := newRotationSummary(ShopSnapshot{Rotation: "2026-10-20T00:00:00Z"})
+	digest := newShopDigest(ShopSnapshot{Rotation: "2026-10-20T00:00:00Z"})
+	tests := []struct {
+		name            string
+		mode            string
+		rotationSummary bool
+		want            []bool
+	}{
+		{"instant", emailModeInstant, false, []bool{true, false, false}},
+		{"instant with rotation summaries", emailModeInstant, true, []bool{true, true, false}},
+		{"digest", emailModeDigest, true, []bool{false, false, true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useTestOutbox(t)
+			globalStore.Settings.Notifications.RotationSummary = test.rotationSummary
+			notifier := &smtpNotifier{settings: SMTPSettings{Mode: test.mode}}
+			for index, notification := range []Notification{match, summary, digest} {
+				if got := notifier.Accepts(notification); got != test.want[index] {
+					t.Errorf("Accepts(%+v) = %v, want %v", notification, got, test.want[index])
+				}
+			}
+		})
+	}
+}
+
+func TestSmtpNotifierSendsOneDigestPerRotation(t *testing.T) {
+	certificate, pool := newTestCertificate(t)
+	server := startFakeSMTPServer(t, certificate, false, true, "secret")
+	notifier, err := newSmtpNotifier(SMTPSettings{Host: "127.0.0.1", Port: server.port(), From: "shop@example.com", To: []string{"a@example.com"}, Mode: emailModeDigest}, pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	useTestOutbox(t, notifier)
+	snapshot := ShopSnapshot{Account: "player", Rotation: "2026-10-20T00:00:00Z", Offers: []Offer{{LevelId: "a", Skin: Skin{Name: "Prime Vandal"}, Price: 1775}}}
+	if errs := sendRotationSummary(snapshot); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	due, _ := globalStore.Outbox.due(time.Now())
+	if len(due) != 1 || !due[0].Notification.Digest {
+		t.Fatalf("outbox = %+v, want the digest", due)
+	}
+	deliverOutboxEntry(context.Background(), due[0])
+	select {
+	case session := <-server.received:
+		if !strings.Contains(session.Data, "Prime Vandal") {
+			t.Errorf("digest = %q", session.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the digest was not sent")
+	}
+	if logEntries, _ := globalStore.DeliveryLog.Recent(10); len(logEntries) != 1 || logEntries[0].Status != deliverySent {
+		t.Errorf("delivery log = %+v", logEntries)
+	}
+	if !globalStore.NotificationHistory.WasSent(digestKey(snapshot, notifier.Name())) {
+		t.Errorf("the digest was not saved in the history: %v", globalStore.NotificationHistory.Sent)
+	}
+	if errs := sendRotationSummary(snapshot); len(errs) > 0 || globalStore.Outbox.Pending() != 0 {
+		t.Errorf("the digest was queued again: %v, %+v", errs, globalStore.Outbox.Entries)
+	}
+}
+
+func TestNewSmtpNotifierDefaults(t *testing.T) {
+	tests := []struct {
+		settings SMTPSettings
+		wantPort int
+		wantErr  bool
+	}{
+		{SMTPSettings{Host: "mail", From: "a", To: []string{"b"}}, 587, false},
+		{SMTPSettings{Host: "mail", From: "a", To: []string{"b"}, Security: smtpSecurityTLS}, 465, false},
+		{SMTPSettings{Host: "mail", From: "a", To: []string{"b"}, Port: 2525}, 2525, false},
+		{SMTPSettings{From: "a", To: []string{"b"}}, 0, true},
+		{SMTPSettings{Host: "mail", To: []string{"b"}}, 0, true},
+		{SMTPSettings{Host: "mail", From: "a"}, 0, true},
+		{SMTPSettings{Host: "mail", From: "a", To: []string{"b"}, Security: "ssl"}, 0, true},
+		{SMTPSettings{Host: "mail", From: "a", To: []string{"b"}, Mode: "weekly"}, 0, true},
+	}
+	for _, test := range tests {
+		notifier, err := newSmtpNotifier(test.settings, nil)
+		if (err != nil) != test.wantErr {
+			t.Errorf("newSmtpNotifier(%+v) error = %v", test.settings, err)
+			continue
+		}
+		if err == nil && notifier.settings.Port != test.wantPort {
+			t.Errorf("newSmtpNotifier(%+v) port = %d, want %d", test.settings, notifier.settings.Port, test.wantPort)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTelegramAPIBaseURL = "https://api.telegram.org"
+	telegramPollTimeout       = 15
+	telegramShopFile          = "telegram-shop.json"
+)
+
+type TelegramSettings struct {
+	BotToken   string `json:"botToken"`
+	ChatID     string `json:"chatId"`
+	APIBaseURL string `json:"apiBaseUrl,omitempty"`
+}
+
+type telegramUpdatesResponse struct {
+	Result []struct {
+		UpdateID int64 `json:"update_id"`
+		Message  *struct {
+			Text string `json:"text"`
+			Chat struct {
+				ID int64 `json:"id"`
+			} `json:"chat"`
+		} `json:"message"`
+	} `json:"result"`
+}
+
+type telegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+type telegramNotifier struct {
+	settings TelegramSettings
+	mutex    sync.Mutex
+	path     string
+	latest   ShopSnapshot
+	offset   int64
+	wishlist func() []Skin
+}
+
+func newTelegramNotifier(settings TelegramSettings) *telegramNotifier {
+	if settings.APIBaseURL == "" {
+		settings.APIBaseURL = defaultTelegramAPIBaseURL
+	}
+	settings.APIBaseURL = strings.TrimSuffix(settings.APIBaseURL, "/")
+	notifier := &telegramNotifier{
+		settings: settings,
+		path:     statePath(telegramShopFile),
+		wishlist: func() []Skin {
+			return globalStore.Ui.selectedSkinsListBox.AllSkins
+		},
+	}
+	if err := notifier.loadLatest(); err != nil {
+		log.Printf("telegram: %s: %v", notifier.path, err)
+	}
+	return notifier
+}
+
+func (notifier *telegramNotifier) loadLatest() error {
+	data, err := os.ReadFile(notifier.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snapshot ShopSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	notifier.mutex.Lock()
+	notifier.latest = snapshot
+	notifier.mutex.Unlock()
+	return nil
+}
+
+func (notifier *telegramNotifier) methodURL(method string) string {
+	return notifier.settings.APIBaseURL + "/bot" + notifier.settings.BotToken + "/" + method
+}
+
+func (notifier *telegramNotifier) sendMessage(ctx context.Context, text string) error {
+	message := telegramMessage{ChatID: notifier.settings.ChatID, Text: text, ParseMode: "HTML"}
+	if err := postJSON(ctx, notifier.methodURL("sendMessage"), message, nil); err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
+
+func (notifier *telegramNotifier) Name() string {
+	return "telegram:" + notifier.settings.ChatID
+}
+
+func (notifier *telegramNotifier) Notify(ctx context.Context, notification Notification) error {
+	text := "<b>" + html.EscapeString(notification.Title) + "</b>\n" + html.EscapeString(notification.Message)
+	if notification.Account != "" {
+		text += "\n" + html.EscapeString(tr("Account")) + ": " + html.EscapeString(notification.Account)
+	}
+	if video := notification.Match.Skin.Video; video != "" {
+		text += "\n<a href=\"" + html.EscapeString(video) + "\">" + html.EscapeString(tr("Watch the video")) + "</a>"
+	}
+	return notifier.sendMessage(ctx, text)
+}
+
+func (notifier *telegramNotifier) NotifyShop(ctx context.Context, snapshot ShopSnapshot) error {
+	notifier.mutex.Lock()
+	notifier.latest = snapshot
+	notifier.mutex.Unlock()
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(notifier.path, data); err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
+
+func (notifier *telegramNotifier) poll(ctx context.Context) {
+	for attempt := 0; ctx.Err() == nil; attempt++ {
+		if err := notifier.handleUpdates(ctx); err == nil {
+			attempt = -1
+			continue
+		}
+		timer := time.NewTimer(backoffDelay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (notifier *telegramNotifier) handleUpdates(ctx context.Context) error {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(notifier.offset, 10))
+	query.Set("timeout", strconv.Itoa(telegramPollTimeout))
+	query.Set("allowed_updates", `["message"]`)
+	req, err := http.NewRequest("GET", notifier.methodURL("getUpdates")+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, telegramPollTimeout*time.Second+requestTimeout)
+	defer cancel()
+	var updates telegramUpdatesResponse
+	if err := sendNotifierRequest(pollCtx, req, &updates); err != nil {
+		return err
+	}
+	for _, update := range updates.Result {
+		notifier.offset = update.UpdateID + 1
+		if update.Message == nil || strconv.FormatInt(update.Message.Chat.ID, 10) != notifier.settings.ChatID {
+			continue
+		}
+		if reply := notifier.reply(update.Message.Text); reply != "" {
+			if err := notifier.sendMessage(ctx, reply); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (notifier *telegramNotifier) reply(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	command, _, _ := strings.Cut(fields[0], "@")
+	notifier.mutex.Lock()
+	snapshot := notifier.latest
+	notifier.mutex.Unlock()
+	switch command {
+	case "/shop":
+		return telegramOfferList(tr("Your Valorant shop"), snapshot, false)
+	case "/nightmarket":
+		return telegramOfferList(tr("Your night market"), snapshot, true)
+	case "/wishlist":
+		wishlist := notifier.wishlist()
+		if len(wishlist) == 0 {
+			return html.EscapeString(tr("Your wishlist is empty."))
+		}
+		lines := []string{"<b>" + html.EscapeString(tr("Your wishlist")) + "</b>"}
+		for _, skin := range wishlist {
+			lines = append(lines, "• "+html.EscapeString(localizedName(skin, locale)+wishlistDecoration(skin)))
+		}
+		return strings.Join(lines, "\n")
+	case "/start", "/help":
+		return html.EscapeString(tr("Commands: /shop, /nightmarket, /wishlist"))
+	}
+	return ""
+}
+
+func telegramOfferList(title string, snapshot ShopSnapshot, nightMarket bool) string {
+	hits := make(map[string]bool)
+	for _, match := range snapshot.Matches {
+		hits[match.LevelId] = true
+	}
+	lines := []string{"<b>" + html.EscapeString(title) + "</b>"}
+	for _, offer := range snapshot.Offers {
+		if offer.NightMarket != nightMarket {
+			continue
+		}
+		line := html.EscapeString(localizedName(offer.Skin, locale))
+		if offer.Price > 0 {
+			line += fmt.Sprintf(" - %d VP", offer.Price)
+		}
+		if hits[offer.LevelId] {
+			line = "<b>" + line + "</b> ⭐"
+		}
+		lines = append(lines, "• "+line)
+	}
+	if len(lines) == 1 {
+		return html.EscapeString(tr("No data yet, the shop has not been fetched."))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeTelegramAPI struct {
+	updates []string
+	sent    []telegramMessage
+}
+
+func (api *fakeTelegramAPI) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottoken/getUpdates":
+			if r.URL.Query().Get("timeout") != fmt.Sprint(telegramPollTimeout) {
+				t.Errorf("getUpdates query = %s", r.URL.RawQuery)
+			}
+			var results []string
+			for index, text := range api.updates {
+				results = append(results, fmt.Sprintf(`{"update_id":%d,"message":{"text":%q,"chat":{"id":42}}}`, index+1, text))
+			}
+			results = append(results, `{"update_id":99,"message":{"text":"/shop","chat":{"id":7}}}`)
+			w.Write([]byte(`{"ok":true,"result":[` + strings.Join(results, ",") + `]}`))
+		case "/bottoken/sendMessage":
+			var message telegramMessage
+			if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+				t.Error(err)
+			}
+			api.sent = append(api.sent, message)
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func newTestTelegramNotifier(t *testing.T, serverURL string, path string) *telegramNotifier {
+	notifier := newTelegramNotifier(TelegramSettings{BotToken: "token", ChatID: "42", APIBaseURL: serverURL + "/"})
+	notifier.path = path
+	if err := notifier.loadLatest(); err != nil {
+		t.Fatal(err)
+	}
+	return notifier
+}
+
+func TestTelegramNotifierCommands(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	snapshot := ShopSnapshot{
+		Account:  "player",
+		Rotation: "2026-10-20T00:00:00Z",
+		Offers: []Offer{
+			{LevelId: "1", Skin: Skin{Name: "Prime Vandal"}, Price: 1775, ExpiresAt: expiresAt},
+			{LevelId: "2", Skin: Skin{Name: "Reaver Sheriff"}, Price: 2175, ExpiresAt: expiresAt},
+			{LevelId: "3", Skin: Skin{Name: "Ion <Phantom>"}, Price: 1065, NightMarket: true},
+		},
+		Matches: []Match{{LevelId: "2"}},
+	}
+	tests := []struct {
+		name     string
+		command  string
+		snapshot *ShopSnapshot
+		wishlist []Skin
+		want     []string
+	}{
+		{"shop", "/shop", &snapshot, nil, []string{"Your Valorant shop", "• Prime Vandal - 1775 VP", "• <b>Reaver Sheriff - 2175 VP</b> ⭐"}},
+		{"night market", "/nightmarket@ShopBot", &snapshot, nil, []string{"Your night market", "• Ion &lt;Phantom&gt; - 1065 VP"}},
+		{"shop before the first fetch", "/shop", nil, nil, []string{"No data yet"}},
+		{"wishlist", "/wishlist", nil, []Skin{{Name: "Prime Vandal", Priority: PriorityMustHave, MaxPrice: 1775}}, []string{"Your wishlist", "• Prime Vandal [Must-have, max 1775 VP]"}},
+		{"empty wishlist", "/wishlist", &snapshot, nil, []string{"Your wishlist is empty."}},
+		{"help", "/help", nil, nil, []string{"/shop, /nightmarket, /wishlist"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &fakeTelegramAPI{updates: []string{test.command, "hello"}}
+			server := stubNotifierServer(t, api.handler(t))
+			notifier := newTestTelegramNotifier(t, server.URL, filepath.Join(t.TempDir(), telegramShopFile))
+			notifier.wishlist = func() []Skin { return test.wishlist }
+			if test.snapshot != nil {
+				if err := notifier.NotifyShop(context.Background(), *test.snapshot); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := notifier.handleUpdates(context.Background()); err != nil {
+				t.Fatalf("handleUpdates() error = %v", err)
+			}
+			if notifier.offset != 100 {
+				t.Errorf("offset = %d, want 100", notifier.offset)
+			}
+			if len(api.sent) != 1 {
+				t.Fatalf("sent %d messages, want 1: %+v", len(api.sent), api.sent)
+			}
+			if api.sent[0].ChatID != "42" || api.sent[0].ParseMode != "HTML" {
+				t.Errorf("message = %+v", api.sent[0])
+			}
+			for _, want := range test.want {
+				if !strings.Contains(api.sent[0].Text, want) {
+					t.Errorf("reply = %q, want %q", api.sent[0].Text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTelegramNotifierKeepsTheShopAcrossRestarts(t *testing.T) {
+	api := &fakeTelegramAPI{updates: []string{"/shop"}}
+	server := stubNotifierServer(t, api.handler(t))
+	path := filepath.Join(t.TempDir(), telegramShopFile)
+	names := SynchronizedMap{}
+	names.UnmarshalJSON([]byte(`{"fr-FR":"Vandal Prime"}`))
+	snapshot := ShopSnapshot{Offers: []Offer{{LevelId: "1", Skin: Skin{Name: "Prime Vandal", LocalizedNames: names}, Price: 1775}}}
+	if err := newTestTelegramNotifier(t, server.URL, path).NotifyShop(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	restarted := newTestTelegramNotifier(t, server.URL, path)
+	if err := restarted.handleUpdates(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.sent) != 1 || !strings.Contains(api.sent[0].Text, "Prime Vandal - 1775 VP") {
+		t.Errorf("reply after a restart = %+v", api.sent)
+	}
+	if name, _ := restarted.latest.Offers[0].Skin.LocalizedNames.Load("fr-FR"); name != "Vandal Prime" {
+		t.Errorf("localized name after a restart = %v", name)
+	}
+}
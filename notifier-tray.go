@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+
+	"github.com/lxn/walk"
+)
+
+type trayNotifier struct {
+	icon *walk.NotifyIcon
+}
+
+func desktopNotifier() (Notifier, error) {
+	if globalStore.Ui.notifyIcon == nil {
+		return nil, nil
+	}
+	return trayNotifier{icon: globalStore.Ui.notifyIcon}, nil
+}
+
+func (notifier trayNotifier) Name() string {
+	return "tray"
+}
+
+func (notifier trayNotifier) Notify(ctx context.Context, notification Notification) error {
+	switch notification.Match.Priority.Urgency() {
+	case UrgencyHigh:
+		return notifier.icon.ShowWarning(notification.Title, notification.Message)
+	case UrgencyNormal:
+		return notifier.icon.ShowInfo(notification.Title, notification.Message)
+	}
+	return notifier.icon.ShowMessage(notification.Title, notification.Message)
+}
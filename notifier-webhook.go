@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const defaultWebhookBody = `{"account": {{json .Account}}, "skin": {{json .Skin}}, "uuid": {{json .UUID}}, "price": {{.Price}}, "remainingSeconds": {{.RemainingSeconds}}, "videoUrl": {{json .VideoURL}}, "message": {{json .Message}}}`
+
+var webhookTemplateFunctions = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+type WebhookSettings struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+type WebhookData struct {
+	Account          string         `json:"account"`
+	Skin             string         `json:"skin"`
+	UUID             string         `json:"uuid"`
+	LevelId          string         `json:"levelId"`
+	Price            int            `json:"price"`
+	RemainingSeconds int            `json:"remainingSeconds"`
+	VideoURL         string         `json:"videoUrl"`
+	IconURL          string         `json:"iconUrl"`
+	Priority         string         `json:"priority"`
+	NightMarket      bool           `json:"nightMarket"`
+	Rule             string         `json:"rule"`
+	Title            string         `json:"title"`
+	Message          string         `json:"message"`
+	Summary          bool           `json:"summary"`
+	Offers           []WebhookOffer `json:"offers,omitempty"`
+	Bundles          []Bundle       `json:"bundles,omitempty"`
+}
+
+type WebhookOffer struct {
+	Skin        string `json:"skin"`
+	UUID        string `json:"uuid"`
+	LevelId     string `json:"levelId"`
+	Price       int    `json:"price"`
+	BasePrice   int    `json:"basePrice"`
+	Discount    int    `json:"discount"`
+	NightMarket bool   `json:"nightMarket"`
+	Wishlist    bool   `json:"wishlist"`
+}
+
+type webhookNotifier struct {
+	settings WebhookSettings
+	body     *template.Template
+	now      func() time.Time
+}
+
+func newWebhookNotifier(settings WebhookSettings) (*webhookNotifier, error) {
+	settings.Method = strings.ToUpper(settings.Method)
+	if settings.Method == "" {
+		settings.Method = http.MethodPost
+	}
+	if settings.Body == "" {
+		settings.Body = defaultWebhookBody
+	}
+	parsedUrl, err := url.Parse(settings.URL)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return nil, errors.New("the webhook URL must start with http:// or https://")
+	}
+	body, err := template.New(settings.Name).Funcs(webhookTemplateFunctions).Parse(settings.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookNotifier{settings: settings, body: body, now: time.Now}, nil
+}
+
+func newWebhookData(notification Notification, now time.Time) WebhookData {
+	match := notification.Match
+	data := WebhookData{
+		Account:     notification.Account,
+		Skin:        localizedName(match.Skin, locale),
+		UUID:        match.Skin.Id,
+		LevelId:     match.LevelId,
+		Price:       match.Skin.Price,
+		VideoURL:    match.Skin.Video,
+		Priority:    string(match.Priority.OrDefault()),
+		NightMarket: match.NightMarket,
+		Rule:        match.Rule,
+		Title:       notification.Title,
+		Message:     notification.Message,
+	}
+	if match.LevelId != "" {
+		data.IconURL = skinIconURL(match.LevelId)
+	}
+	if summary := notification.Summary; summary != nil {
+		data.Summary = true
+		data.Bundles = summary.Bundles
+		hits := make(map[string]bool)
+		for _, hit := range summary.Matches {
+			hits[hit.LevelId] = true
+		}
+		for _, offer := range summary.Offers {
+			data.Offers = append(data.Offers, WebhookOffer{
+				Skin:        localizedName(offer.Skin, locale),
+				UUID:        offer.Skin.Id,
+				LevelId:     offer.LevelId,
+				Price:       offer.Price,
+				BasePrice:   offer.BasePrice,
+				Discount:    offer.DiscountPercent,
+				NightMarket: offer.NightMarket,
+				Wishlist:    hits[offer.LevelId],
+			})
+		}
+	}
+	if !match.ExpiresAt.IsZero() {
+		if remaining := match.ExpiresAt.Sub(now); remaining > 0 {
+			data.RemainingSeconds = int(remaining.Seconds())
+		}
+	}
+	return data
+}
+
+func (notifier *webhookNotifier) Name() string {
+	return "webhook:" + notifier.settings.Name
+}
+
+func (notifier *webhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	var body bytes.Buffer
+	if err := notifier.body.Execute(&body, newWebhookData(notification, notifier.now())); err != nil {
+		return fmt.Errorf("webhook %s: %w", notifier.settings.Name, err)
+	}
+	req, err := http.NewRequest(notifier.settings.Method, notifier.settings.URL, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range notifier.settings.Headers {
+		req.Header.Set(key, value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	if err := sendNotifierRequest(ctx, req, nil); err != nil {
+		return fmt.Errorf("webhook %s: %w", notifier.settings.Name, err)
+	}
+	return nil
+}
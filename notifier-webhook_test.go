@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifierNotify(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	notification := Notification{
+		Title:   appName,
+		Message: `Prime "Vandal" is available`,
+		Account: "player",
+		Match:   Match{LevelId: "LEVEL", Skin: Skin{Id: "SKIN", Name: "Prime Vandal", Price: 1775, Video: "https://example.com/v.mp4"}, Priority: PriorityMustHave, ExpiresAt: now.Add(90 * time.Second), Rule: "primes"},
+	}
+	tests := []struct {
+		name       string
+		settings   WebhookSettings
+		wantMethod string
+		wantBody   string
+		wantHeader string
+	}{
+		{
+			"default body",
+			WebhookSettings{},
+			http.MethodPost,
+			`{"account": "player", "skin": "Prime Vandal", "uuid": "SKIN", "price": 1775, "remainingSeconds": 90, "videoUrl": "https://example.com/v.mp4", "message": "Prime \"Vandal\" is available"}`,
+			"",
+		},
+		{
+			"custom template",
+			WebhookSettings{Method: "put", Headers: map[string]string{"Authorization": "Bearer secret"}, Body: `{"text": {{json (printf "%s (%s, %s)" .Skin .Priority .Rule)}}, "icon": {{json .IconURL}}, "night": {{.NightMarket}}}`},
+			http.MethodPut,
+			`{"text": "Prime Vandal (must-have, primes)", "icon": "https://media.valorant-api.com/weaponskinlevels/level/displayicon.png", "night": false}`,
+			"Bearer secret",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, _ := io.ReadAll(r.Body)
+				if r.Method != test.wantMethod || string(body) != test.wantBody || r.Header.Get("Authorization") != test.wantHeader {
+					t.Errorf("request = %s %s %v, want %s %s", r.Method, body, r.Header, test.wantMethod, test.wantBody)
+				}
+				if !json.Valid(body) {
+					t.Errorf("body %s is not valid JSON", body)
+				}
+			})
+			test.settings.Name = "test"
+			test.settings.URL = server.URL
+			notifier, err := newWebhookNotifier(test.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notifier.now = func() time.Time { return now }
+			if err := notifier.Notify(context.Background(), notification); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if requests != 1 {
+				t.Errorf("Notify() made %d requests, want 1", requests)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierDoesNotRetry(t *testing.T) {
+	requests := 0
+	server := stubNotifierServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	notifier, err := newWebhookNotifier(WebhookSettings{Name: "down", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = notifier.Notify(context.Background(), Notification{})
+	if err == nil || !strings.Contains(err.Error(), "webhook down: HTTP 503") {
+		t.Errorf("Notify() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Notify() made %d requests, want 1", requests)
+	}
+}
+
+func TestNewWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		settings WebhookSettings
+		wantErr  string
+	}{
+		{WebhookSettings{URL: "https://example.com/hook"}, ""},
+		{WebhookSettings{URL: "ftp://example.com/hook"}, "must start with http"},
+		{WebhookSettings{URL: "https://example.com/hook", Body: "{{.Skin"}, "unclosed action"},
+		{WebhookSettings{URL: "https://example.com/%zz"}, "invalid URL escape"},
+	}
+	for _, test := range tests {
+		_, err := newWebhookNotifier(test.settings)
+		if test.wantErr == "" && err != nil || test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+			t.Errorf("newWebhookNotifier(%+v) error = %v, want %q", test.settings, err, test.wantErr)
+		}
+	}
+}
+
+func TestNewWebhookDataSummary(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	summary := &ShopSnapshot{
+		Offers: []Offer{
+			{LevelId: "1", Skin: Skin{Id: "a", Name: "Prime Vandal"}, Price: 1775, BasePrice: 1775},
+			{LevelId: "2", Skin: Skin{Id: "b", Name: "Ion Phantom"}, Price: 1065, BasePrice: 1775, DiscountPercent: 40, NightMarket: true},
+		},
+		Matches: []Match{{LevelId: "2"}},
+		Bundles: []Bundle{{Id: "bundle", Name: "Prime", Price: 7100}},
+	}
+	data := newWebhookData(Notification{Title: "Shop", Summary: summary}, now)
+	if !data.Summary || len(data.Offers) != 2 || len(data.Bundles) != 1 {
+		t.Fatalf("newWebhookData() = %+v", data)
+	}
+	if data.Offers[0].Wishlist || !data.Offers[1].Wishlist || data.Offers[1].Discount != 40 || !data.Offers[1].NightMarket || data.Offers[1].BasePrice != 1775 {
+		t.Errorf("newWebhookData() offers = %+v", data.Offers)
+	}
+	if data.RemainingSeconds != 0 || data.IconURL != "" {
+		t.Errorf("newWebhookData() = %+v, want no match fields", data)
+	}
+}
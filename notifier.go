@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const appName = "Valorant Shopwatcher"
+
+type Notification struct {
+	Title   string
+	Message string
+	Account string
+	Match   Match
+	Summary *ShopSnapshot
+	Digest  bool
+}
+
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, notification Notification) error
+}
+
+type NotificationFilter interface {
+	Accepts(notification Notification) bool
+}
+
+type ShopSnapshot struct {
+	Account  string
+	Rotation string
+	Offers   []Offer
+	Matches  []Match
+	Bundles  []Bundle
+	Wishlist []Skin
+}
+
+type ShopNotifier interface {
+	NotifyShop(ctx context.Context, snapshot ShopSnapshot) error
+}
+
+type OutputNotifier interface {
+	NotifyWithOutput(ctx context.Context, notification Notification) (string, error)
+}
+
+func newMatchNotification(match Match) Notification {
+	skinName := localizedName(match.Skin, locale)
+	message := tr("%s is available in your Valorant shop!", skinName)
+	if match.NightMarket {
+		message = tr("%s is available in your night market!", skinName)
+	}
+	if match.Skin.Price > 0 {
+		message += fmt.Sprintf(" (%d VP)", match.Skin.Price)
+	}
+	title := appName
+	if match.Priority.Urgency() == UrgencyHigh {
+		title += " - " + match.Priority.Label()
+	}
+	return Notification{Title: title, Message: message, Account: globalStore.User.Login, Match: match}
+}
+
+func newShopSnapshot(offers []Offer, matches []Match) ShopSnapshot {
+	return ShopSnapshot{Account: globalStore.User.Login, Rotation: shopRotation(offers), Offers: offers, Matches: matches, Bundles: globalStore.CurrentBundles, Wishlist: globalStore.Ui.selectedSkinsListBox.AllSkins}
+}
+
+func shopRotation(offers []Offer) string {
+	for _, offer := range offers {
+		if !offer.NightMarket && !offer.ExpiresAt.IsZero() {
+			return offer.ExpiresAt.UTC().Round(time.Hour).Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+func skinIconURL(levelId string) string {
+	return "https://media.valorant-api.com/weaponskinlevels/" + strings.ToLower(levelId) + "/displayicon.png"
+}
+
+func uniqueNotifiers(notifiers []Notifier) ([]Notifier, []error) {
+	var unique []Notifier
+	var errs []error
+	names := make(map[string]bool)
+	for _, notifier := range notifiers {
+		if names[notifier.Name()] {
+			errs = append(errs, fmt.Errorf("notifications: %s is configured more than once, give each channel its own name", notifier.Name()))
+			continue
+		}
+		names[notifier.Name()] = true
+		unique = append(unique, notifier)
+	}
+	return unique, errs
+}
+
+func setupNotifiers() []error {
+	var errs []error
+	if notifier, err := desktopNotifier(); err != nil {
+		errs = append(errs, fmt.Errorf("desktop notifications: %w", err))
+	} else if notifier != nil {
+		globalStore.Notifiers = append(globalStore.Notifiers, notifier)
+	}
+	if settings := globalStore.Settings.Notifications.Discord; settings.WebhookURL != "" || len(settings.AccountWebhookURLs) > 0 {
+		globalStore.Notifiers = append(globalStore.Notifiers, newDiscordNotifier(settings))
+	}
+	rootCAs, err := loadRootCAs(globalStore.Settings.Network.CACertFiles)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("notifications: %w", err))
+	}
+	if settings := globalStore.Settings.Notifications.Email; settings.Host != "" {
+		if notifier, err := newSmtpNotifier(settings, rootCAs); err != nil {
+			errs = append(errs, fmt.Errorf("email notifications: %w", err))
+		} else {
+			globalStore.Notifiers = append(globalStore.Notifiers, notifier)
+		}
+	}
+	if settings := globalStore.Settings.Notifications.Telegram; settings.BotToken != "" && settings.ChatID != "" {
+		globalStore.Notifiers = append(globalStore.Notifiers, newTelegramNotifier(settings))
+	}
+	if settings := globalStore.Settings.Notifications.Ntfy; settings.Topic != "" {
+		globalStore.Notifiers = append(globalStore.Notifiers, newNtfyNotifier(settings))
+	}
+	if settings := globalStore.Settings.Notifications.Gotify; settings.ServerURL != "" && settings.Token != "" {
+		globalStore.Notifiers = append(globalStore.Notifiers, newGotifyNotifier(settings))
+	}
+	for index, settings := range globalStore.Settings.Notifications.Webhooks {
+		if settings.Name == "" {
+			settings.Name = fmt.Sprintf("#%d", index+1)
+		}
+		notifier, err := newWebhookNotifier(settings)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", settings.Name, err))
+			continue
+		}
+		globalStore.Notifiers = append(globalStore.Notifiers, notifier)
+	}
+	for index, settings := range globalStore.Settings.Notifications.Commands {
+		notifier, err := newCommandNotifier(settings)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("command #%d: %w", index+1, err))
+			continue
+		}
+		globalStore.Notifiers = append(globalStore.Notifiers, notifier)
+	}
+	for _, raw := range globalStore.Settings.Notifications.URLs {
+		notifier, err := parseNotificationURL(raw, rootCAs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifications: %w", err))
+			continue
+		}
+		globalStore.Notifiers = append(globalStore.Notifiers, notifier)
+	}
+	var duplicateErrs []error
+	globalStore.Notifiers, duplicateErrs = uniqueNotifiers(globalStore.Notifiers)
+	errs = append(errs, duplicateErrs...)
+	for _, notifier := range globalStore.Notifiers {
+		if telegram, ok := notifier.(*telegramNotifier); ok {
+			go telegram.poll(appContext)
+		}
+	}
+	return errs
+}
+
+func acceptsNotification(notifier Notifier, notification Notification) bool {
+	if filter, ok := notifier.(NotificationFilter); ok {
+		return filter.Accepts(notification)
+	}
+	return !notification.Digest && (notification.Summary == nil || globalStore.Settings.Notifications.RotationSummary)
+}
+
+func sendShopSnapshot(ctx context.Context, snapshot ShopSnapshot) []error {
+	var errs []error
+	for _, notifier := range globalStore.Notifiers {
+		if shopNotifier, ok := notifier.(ShopNotifier); ok {
+			if err := shopNotifier.NotifyShop(ctx, snapshot); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+func openURL(url string) error {
+	switch runtime.GOOS {
+	case "windows":
+		return exec.Command("explorer", url).Start()
+	case "darwin":
+		return exec.Command("open", url).Start()
+	}
+	return exec.Command("xdg-open", url).Start()
+}
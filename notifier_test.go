@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUniqueNotifiers(t *testing.T) {
+	first, err := parseNotificationURL("webhook+https://hooks.slack.com/services/T0/B0/first", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseNotificationURL("webhook+https://hooks.slack.com/services/T0/B0/second", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		notifiers []Notifier
+		wantNames []string
+		wantErrs  int
+	}{
+		{"distinct", []Notifier{&fakeNotifier{name: "a"}, &fakeNotifier{name: "b"}}, []string{"a", "b"}, 0},
+		{"duplicate", []Notifier{&fakeNotifier{name: "a"}, &fakeNotifier{name: "b"}, &fakeNotifier{name: "a"}}, []string{"a", "b"}, 1},
+		{"webhooks on the same host", []Notifier{first, second}, []string{first.Name(), second.Name()}, 0},
+		{"commands with the same name", []Notifier{&commandNotifier{settings: CommandSettings{Name: "notify.sh"}}, &commandNotifier{settings: CommandSettings{Name: "notify.sh"}}}, []string{"command:notify.sh"}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			unique, errs := uniqueNotifiers(test.notifiers)
+			var names []string
+			for _, notifier := range unique {
+				names = append(names, notifier.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(test.wantNames, ",") || len(errs) != test.wantErrs {
+				t.Errorf("uniqueNotifiers() = %v, %v, want %v and %d errors", names, errs, test.wantNames, test.wantErrs)
+			}
+		})
+	}
+}
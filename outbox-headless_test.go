@@ -0,0 +1,52 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func captureErrors(t *testing.T) *bytes.Buffer {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &output
+}
+
+func TestDeliverOutboxEntryDropsAfterTheLastAttempt(t *testing.T) {
+	notifier := &fakeNotifier{name: "fake", errs: []error{errors.New("timeout")}}
+	useTestOutbox(t, notifier)
+	output := captureErrors(t)
+	entry := OutboxEntry{Key: "key", Channel: "fake", NotBefore: time.Now(), Attempts: maxDeliveryAttempts - 1}
+	if err := globalStore.Outbox.Enqueue([]OutboxEntry{entry}); err != nil {
+		t.Fatal(err)
+	}
+	deliverOutboxEntry(context.Background(), entry)
+	if globalStore.Outbox.Pending() != 0 {
+		t.Errorf("outbox = %+v, want the entry dropped", globalStore.Outbox.Entries)
+	}
+	if logEntries, _ := globalStore.DeliveryLog.Recent(10); len(logEntries) != 1 || logEntries[0].Status != deliveryDropped || logEntries[0].Attempt != maxDeliveryAttempts {
+		t.Errorf("delivery log = %+v", logEntries)
+	}
+	if !strings.Contains(output.String(), "timeout (after 8 attempts)") {
+		t.Errorf("logged %q, want the delivery error", output.String())
+	}
+}
+
+func TestDeliverOutboxEntryReportsSaveErrors(t *testing.T) {
+	useTestOutbox(t, &fakeNotifier{name: "fake"})
+	globalStore.DeliveryLog = newDeliveryLog(filepath.Join(t.TempDir(), "missing", deliveryLogFile))
+	output := captureErrors(t)
+	deliverOutboxEntry(context.Background(), OutboxEntry{Key: "key", Channel: "fake", NotBefore: time.Now()})
+	if !strings.Contains(output.String(), tr("The app could not save the data")) {
+		t.Errorf("logged %q, want the save error", output.String())
+	}
+}
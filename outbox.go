@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	outboxFile            = "outbox.json"
+	maxDeliveryAttempts   = 8
+	minDeliveryRetryDelay = 30 * time.Second
+	maxDeliveryRetryDelay = time.Hour
+	outboxIdleDelay       = time.Hour
+)
+
+type OutboxEntry struct {
+	Key          string       `json:"key"`
+	Channel      string       `json:"channel"`
+	Notification Notification `json:"notification"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	NotBefore    time.Time    `json:"notBefore"`
+	Attempts     int          `json:"attempts"`
+	LastError    string       `json:"lastError,omitempty"`
+}
+
+type Outbox struct {
+	mutex   sync.Mutex
+	path    string
+	wake    chan struct{}
+	Entries []OutboxEntry `json:"entries"`
+}
+
+func newOutbox(path string) *Outbox {
+	return &Outbox{path: path, wake: make(chan struct{}, 1), Entries: []OutboxEntry{}}
+}
+
+func loadOutbox(path string) (*Outbox, error) {
+	outbox := newOutbox(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return outbox, nil
+	}
+	if err != nil {
+		return outbox, err
+	}
+	if err := json.Unmarshal(data, outbox); err != nil {
+		return newOutbox(path), err
+	}
+	return outbox, nil
+}
+
+func (outbox *Outbox) save() error {
+	data, err := json.MarshalIndent(outbox, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(outbox.path, data)
+}
+
+func (outbox *Outbox) Has(key string) bool {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	for _, entry := range outbox.Entries {
+		if entry.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (outbox *Outbox) Pending() int {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	return len(outbox.Entries)
+}
+
+func (outbox *Outbox) Enqueue(entries []OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	outbox.mutex.Lock()
+	outbox.Entries = append(outbox.Entries, entries...)
+	err := outbox.save()
+	outbox.mutex.Unlock()
+	outbox.Wake()
+	return err
+}
+
+func (outbox *Outbox) Wake() {
+	select {
+	case outbox.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (outbox *Outbox) due(now time.Time) ([]OutboxEntry, time.Time) {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	var due []OutboxEntry
+	next := now.Add(outboxIdleDelay)
+	for _, entry := range outbox.Entries {
+		if !entry.NotBefore.After(now) {
+			due = append(due, entry)
+		} else if entry.NotBefore.Before(next) {
+			next = entry.NotBefore
+		}
+	}
+	return due, next
+}
+
+func (outbox *Outbox) update(key string, entry *OutboxEntry) error {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	for index := range outbox.Entries {
+		if outbox.Entries[index].Key != key {
+			continue
+		}
+		if entry == nil {
+			outbox.Entries = append(outbox.Entries[:index], outbox.Entries[index+1:]...)
+		} else {
+			outbox.Entries[index] = *entry
+		}
+		break
+	}
+	return outbox.save()
+}
+
+func deliveryRetryDelay(attempts int) time.Duration {
+	delay := minDeliveryRetryDelay << (attempts - 1)
+	if delay > maxDeliveryRetryDelay || delay <= 0 {
+		delay = maxDeliveryRetryDelay
+	}
+	return delay
+}
+
+func findNotifier(name string) Notifier {
+	for _, notifier := range globalStore.Notifiers {
+		if notifier.Name() == name {
+			return notifier
+		}
+	}
+	return nil
+}
+
+func deliverNotification(ctx context.Context, notification Notification) []error {
+	now := time.Now()
+	if globalStore.Snoozes != nil && globalStore.Snoozes.IsSnoozed(notification, now) {
+		return nil
+	}
+	notBefore := now
+	if endsAt, quiet := globalStore.Settings.Notifications.QuietHours.EndsAt(now); quiet {
+		notBefore = endsAt
+	}
+	var entries []OutboxEntry
+	for _, notifier := range globalStore.Notifiers {
+		if !acceptsNotification(notifier, notification) {
+			continue
+		}
+		key := notificationKey(notification, notifier.Name())
+		if globalStore.NotificationHistory.WasSent(key) || globalStore.Outbox.Has(key) {
+			continue
+		}
+		entries = append(entries, OutboxEntry{Key: key, Channel: notifier.Name(), Notification: notification, CreatedAt: now, NotBefore: notBefore})
+	}
+	if err := globalStore.Outbox.Enqueue(entries); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func runOutbox(ctx context.Context) {
+	outbox := globalStore.Outbox
+	for ctx.Err() == nil {
+		due, next := outbox.due(time.Now())
+		for _, entry := range due {
+			deliverOutboxEntry(ctx, entry)
+		}
+		if len(due) > 0 {
+			continue
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+		case <-outbox.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+func nextDeliveryAttempt(now time.Time, attempts int, err error) time.Time {
+	delay := deliveryRetryDelay(attempts)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > delay {
+		delay = httpErr.RetryAfter
+	}
+	next := now.Add(delay)
+	if endsAt, quiet := globalStore.Settings.Notifications.QuietHours.EndsAt(next); quiet {
+		next = endsAt
+	}
+	return next
+}
+
+func recordDelivery(entry OutboxEntry, status string, deliveryErr error, output string, next *OutboxEntry) {
+	errs := []error{globalStore.DeliveryLog.Append(entry, status, deliveryErr, output)}
+	if status == deliverySent {
+		globalStore.NotificationHistory.MarkSent(entry.Key, time.Now())
+		errs = append(errs, globalStore.NotificationHistory.Save())
+	}
+	errs = append(errs, globalStore.Outbox.update(entry.Key, next))
+	for _, err := range errs {
+		if err != nil {
+			showError(tr("The app could not save the data"), err)
+			return
+		}
+	}
+}
+
+func deliverOutboxEntry(ctx context.Context, entry OutboxEntry) {
+	now := time.Now()
+	if globalStore.Snoozes != nil && globalStore.Snoozes.IsSnoozed(entry.Notification, now) {
+		recordDelivery(entry, deliveryDropped, errors.New("this notification was snoozed"), "", nil)
+		return
+	}
+	if endsAt, quiet := globalStore.Settings.Notifications.QuietHours.EndsAt(now); quiet {
+		entry.NotBefore = endsAt
+		if err := globalStore.Outbox.update(entry.Key, &entry); err != nil {
+			showError(tr("The app could not save the data"), err)
+		}
+		return
+	}
+	entry.Attempts++
+	notifier := findNotifier(entry.Channel)
+	if notifier == nil {
+		recordDelivery(entry, deliveryDropped, errors.New("this channel is not configured anymore"), "", nil)
+		return
+	}
+	var output string
+	var err error
+	if outputNotifier, ok := notifier.(OutputNotifier); ok {
+		output, err = outputNotifier.NotifyWithOutput(ctx, entry.Notification)
+	} else {
+		err = notifier.Notify(ctx, entry.Notification)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if err == nil {
+		recordDelivery(entry, deliverySent, nil, output, nil)
+		return
+	}
+	if entry.Attempts >= maxDeliveryAttempts {
+		recordDelivery(entry, deliveryDropped, err, output, nil)
+		showError(tr("A notification could not be sent to %s", entry.Channel), fmt.Errorf("%w (after %d attempts)", err, entry.Attempts))
+		return
+	}
+	entry.LastError = err.Error()
+	entry.NotBefore = nextDeliveryAttempt(time.Now(), entry.Attempts, err)
+	recordDelivery(entry, deliveryFailed, err, output, &entry)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeNotifier struct {
+	name  string
+	errs  []error
+	calls int
+}
+
+func (notifier *fakeNotifier) Name() string {
+	return notifier.name
+}
+
+func (notifier *fakeNotifier) Notify(ctx context.Context, notification Notification) error {
+	notifier.calls++
+	if len(notifier.errs) == 0 {
+		return nil
+	}
+	err := notifier.errs[0]
+	notifier.errs = notifier.errs[1:]
+	return err
+}
+
+func useTestOutbox(t *testing.T, notifiers ...Notifier) {
+	previous := globalStore
+	t.Cleanup(func() { globalStore = previous })
+	dir := t.TempDir()
+	history, err := loadNotificationHistory(filepath.Join(dir, notificationHistoryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalStore.Settings = defaultSettings()
+	globalStore.Notifiers = notifiers
+	globalStore.NotificationHistory = history
+	globalStore.Outbox = newOutbox(filepath.Join(dir, outboxFile))
+	globalStore.DeliveryLog = newDeliveryLog(filepath.Join(dir, deliveryLogFile))
+	globalStore.Snoozes = newSnoozes(filepath.Join(dir, snoozesFile))
+}
+
+func TestDeliveryRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+	for _, test := range tests {
+		if got := deliveryRetryDelay(test.attempts); got != test.want {
+			t.Errorf("deliveryRetryDelay(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
+
+func TestNextDeliveryAttempt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 21, 50, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		attempts   int
+		err        error
+		quietHours QuietHours
+		want       time.Time
+	}{
+		{"backoff", 2, errors.New("timeout"), QuietHours{}, now.Add(time.Minute)},
+		{"longer Retry-After", 1, &HTTPError{StatusCode: 429, RetryAfter: 5 * time.Minute}, QuietHours{}, now.Add(5 * time.Minute)},
+		{"shorter Retry-After", 3, &HTTPError{StatusCode: 429, RetryAfter: time.Second}, QuietHours{}, now.Add(2 * time.Minute)},
+		{"retry during quiet hours", 6, errors.New("timeout"), QuietHours{Start: "22:00", End: "07:00"}, time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)},
+		{"retry before quiet hours", 1, errors.New("timeout"), QuietHours{Start: "22:00", End: "07:00"}, now.Add(30 * time.Second)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useTestOutbox(t)
+			globalStore.Settings.Notifications.QuietHours = test.quietHours
+			if got := nextDeliveryAttempt(now, test.attempts, test.err); !got.Equal(test.want) {
+				t.Errorf("nextDeliveryAttempt() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDeliverOutboxEntry(t *testing.T) {
+	now := time.Now()
+	quietHours := QuietHours{Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04")}
+	notification := Notification{Title: appName, Account: "player", Match: Match{LevelId: "LEVEL", Skin: Skin{Id: "SKIN", Name: "Prime Vandal"}}}
+	tests := []struct {
+		name          string
+		channel       string
+		attempts      int
+		errs          []error
+		quietHours    QuietHours
+		snoozed       bool
+		wantCalls     int
+		wantStatus    string
+		wantPending   bool
+		wantAttempts  int
+		wantNotBefore time.Duration
+		wantSent      bool
+	}{
+		{name: "sent", channel: "fake", wantCalls: 1, wantStatus: deliverySent, wantSent: true},
+		{name: "failed", channel: "fake", errs: []error{errors.New("timeout")}, wantCalls: 1, wantStatus: deliveryFailed, wantPending: true, wantAttempts: 1, wantNotBefore: minDeliveryRetryDelay},
+		{name: "failed with Retry-After", channel: "fake", errs: []error{&HTTPError{StatusCode: 429, RetryAfter: 10 * time.Minute}}, wantCalls: 1, wantStatus: deliveryFailed, wantPending: true, wantAttempts: 1, wantNotBefore: 10 * time.Minute},
+		{name: "snoozed", channel: "fake", snoozed: true, wantStatus: deliveryDropped},
+		{name: "quiet hours", channel: "fake", quietHours: quietHours, wantPending: true, wantNotBefore: time.Hour},
+		{name: "unknown channel", channel: "removed", wantStatus: deliveryDropped},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notifier := &fakeNotifier{name: "fake", errs: test.errs}
+			useTestOutbox(t, notifier)
+			globalStore.Settings.Notifications.QuietHours = test.quietHours
+			if test.snoozed {
+				if err := globalStore.Snoozes.SnoozeSkin("SKIN", now.Add(time.Hour)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			entry := OutboxEntry{Key: notificationKey(notification, test.channel), Channel: test.channel, Notification: notification, CreatedAt: now, NotBefore: now, Attempts: test.attempts}
+			if err := globalStore.Outbox.Enqueue([]OutboxEntry{entry}); err != nil {
+				t.Fatal(err)
+			}
+			deliverOutboxEntry(context.Background(), entry)
+			if notifier.calls != test.wantCalls {
+				t.Errorf("Notify() called %d times, want %d", notifier.calls, test.wantCalls)
+			}
+			reloaded, err := loadOutbox(globalStore.Outbox.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pending := len(reloaded.Entries) == 1; pending != test.wantPending {
+				t.Fatalf("saved outbox = %+v, want pending %v", reloaded.Entries, test.wantPending)
+			}
+			if test.wantPending {
+				saved := reloaded.Entries[0]
+				if saved.Attempts != test.wantAttempts {
+					t.Errorf("attempts = %d, want %d", saved.Attempts, test.wantAttempts)
+				}
+				if delay := saved.NotBefore.Sub(now); delay < test.wantNotBefore-time.Minute || delay > test.wantNotBefore+time.Minute {
+					t.Errorf("next attempt in %v, want %v", delay, test.wantNotBefore)
+				}
+			}
+			logEntries, err := globalStore.DeliveryLog.Recent(10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.wantStatus == "" && len(logEntries) != 0 || test.wantStatus != "" && (len(logEntries) != 1 || logEntries[0].Status != test.wantStatus) {
+				t.Errorf("delivery log = %+v, want %q", logEntries, test.wantStatus)
+			}
+			if got := globalStore.NotificationHistory.WasSent(entry.Key); got != test.wantSent {
+				t.Errorf("WasSent() = %v, want %v", got, test.wantSent)
+			}
+		})
+	}
+}
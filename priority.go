@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const valorantPointsId = "85ad13f7-3d1b-5128-9eb2-7cd8ee0b5741"
+
+type Priority string
+
+const (
+	PriorityMustHave   Priority = "must-have"
+	PriorityNiceToHave Priority = "nice-to-have"
+	PriorityWatching   Priority = "watching"
+)
+
+var priorities = []Priority{PriorityMustHave, PriorityNiceToHave, PriorityWatching}
+
+type Urgency int
+
+const (
+	UrgencyLow Urgency = iota
+	UrgencyNormal
+	UrgencyHigh
+)
+
+type Match struct {
+	LevelId     string
+	Skin        Skin
+	Priority    Priority
+	MaxPrice    int
+	NightMarket bool
+	ExpiresAt   time.Time
+	Rule        string
+}
+
+func parsePriority(value string) (Priority, bool) {
+	normalized := strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case "":
+		return PriorityNiceToHave, true
+	case "must", "musthave", "must-have":
+		return PriorityMustHave, true
+	case "nice", "nicetohave", "nice-to-have":
+		return PriorityNiceToHave, true
+	case "watch", "watching":
+		return PriorityWatching, true
+	}
+	return PriorityNiceToHave, false
+}
+
+func (priority Priority) OrDefault() Priority {
+	if priority == "" {
+		return PriorityNiceToHave
+	}
+	return priority
+}
+
+func (priority Priority) Label() string {
+	switch priority.OrDefault() {
+	case PriorityMustHave:
+		return tr("Must-have")
+	case PriorityWatching:
+		return tr("Watching")
+	}
+	return tr("Nice to have")
+}
+
+func (priority Priority) Urgency() Urgency {
+	switch priority.OrDefault() {
+	case PriorityMustHave:
+		return UrgencyHigh
+	case PriorityWatching:
+		return UrgencyLow
+	}
+	return UrgencyNormal
+}
+
+func (match Match) IsWithinBudget() bool {
+	return match.MaxPrice <= 0 || match.Skin.Price <= 0 || match.Skin.Price <= match.MaxPrice
+}
+
+func wishlistDecoration(skin Skin) string {
+	decoration := " [" + skin.Priority.Label()
+	if skin.MaxPrice > 0 {
+		decoration += fmt.Sprintf(", max %d VP", skin.MaxPrice)
+	}
+	return decoration + "]"
+}
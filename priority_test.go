@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Priority
+		wantOk bool
+	}{
+		{"", PriorityNiceToHave, true},
+		{"must", PriorityMustHave, true},
+		{" Must Have ", PriorityMustHave, true},
+		{"MUST_HAVE", PriorityMustHave, true},
+		{"nice-to-have", PriorityNiceToHave, true},
+		{"Nice to have", PriorityNiceToHave, true},
+		{"watch", PriorityWatching, true},
+		{"watching", PriorityWatching, true},
+		{"urgent", PriorityNiceToHave, false},
+	}
+	for _, test := range tests {
+		got, ok := parsePriority(test.value)
+		if got != test.want || ok != test.wantOk {
+			t.Errorf("parsePriority(%q) = %q, %v, want %q, %v", test.value, got, ok, test.want, test.wantOk)
+		}
+	}
+}
+
+func TestPriorityUrgency(t *testing.T) {
+	tests := []struct {
+		priority Priority
+		want     Urgency
+	}{
+		{PriorityMustHave, UrgencyHigh},
+		{PriorityNiceToHave, UrgencyNormal},
+		{"", UrgencyNormal},
+		{PriorityWatching, UrgencyLow},
+	}
+	for _, test := range tests {
+		if got := test.priority.Urgency(); got != test.want {
+			t.Errorf("Urgency(%q) = %d, want %d", test.priority, got, test.want)
+		}
+	}
+	for index := 1; index < len(priorities); index++ {
+		if priorities[index-1].Urgency() <= priorities[index].Urgency() {
+			t.Errorf("%q is listed before %q but is not more urgent", priorities[index-1], priorities[index])
+		}
+	}
+}
+
+func TestIsWithinBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxPrice int
+		price    int
+		want     bool
+	}{
+		{"no ceiling", 0, 2175, true},
+		{"negative ceiling", -1, 2175, true},
+		{"unknown price", 1000, 0, true},
+		{"below the ceiling", 1775, 1275, true},
+		{"at the ceiling", 1775, 1775, true},
+		{"above the ceiling", 1775, 2175, false},
+	}
+	for _, test := range tests {
+		match := Match{MaxPrice: test.maxPrice, Skin: Skin{Price: test.price}}
+		if got := match.IsWithinBudget(); got != test.want {
+			t.Errorf("%s: IsWithinBudget() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFindMatchesKeepsTheMostUrgentMatch(t *testing.T) {
+	offers := []Offer{
+		{LevelId: "1", Skin: Skin{Id: "a", Name: "Prime Vandal", Price: 1775}, Attributes: SkinAttributes{Weapon: "Vandal", Collection: "Prime"}},
+		{LevelId: "2", Skin: Skin{Id: "b", Name: "Reaver Sheriff", Price: 2175}, Attributes: SkinAttributes{Weapon: "Sheriff", Collection: "Reaver"}},
+		{LevelId: "3", Skin: Skin{Id: "c", Name: "Ion Phantom", Price: 1775}, Attributes: SkinAttributes{Weapon: "Phantom", Collection: "Ion"}},
+	}
+	tests := []struct {
+		name     string
+		wishlist []Skin
+		rules    []WatchRule
+		want     []string
+	}{
+		{
+			"wishlist and rule on the same offer",
+			[]Skin{{Id: "a", Priority: PriorityWatching}},
+			[]WatchRule{{Name: "prime", Expression: "collection == 'Prime'", Priority: PriorityMustHave}},
+			[]string{"1:must-have:prime"},
+		},
+		{
+			"first match wins on equal urgency",
+			[]Skin{{Id: "a"}},
+			[]WatchRule{{Name: "vandals", Expression: "weapon == 'Vandal'"}},
+			[]string{"1:nice-to-have:"},
+		},
+		{
+			"urgent match over budget is skipped",
+			[]Skin{{Id: "b", Priority: PriorityWatching}},
+			[]WatchRule{{Name: "reaver", Expression: "collection == 'Reaver'", Priority: PriorityMustHave, MaxPrice: 1775}},
+			[]string{"2:watching:"},
+		},
+		{
+			"sorted by urgency",
+			[]Skin{{Id: "a", Priority: PriorityWatching}, {Id: "b"}, {Id: "c", Priority: PriorityMustHave}},
+			nil,
+			[]string{"3:must-have:", "2:nice-to-have:", "1:watching:"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches, errs := findMatches(test.wishlist, test.rules, offers)
+			if len(errs) != 0 {
+				t.Fatalf("findMatches() errors = %v", errs)
+			}
+			var got []string
+			for _, match := range matches {
+				got = append(got, match.LevelId+":"+string(match.Priority)+":"+match.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("findMatches() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWishlistDecoration(t *testing.T) {
+	tests := []struct {
+		skin Skin
+		want string
+	}{
+		{Skin{}, " [Nice to have]"},
+		{Skin{Priority: PriorityMustHave, MaxPrice: 1775}, " [Must-have, max 1775 VP]"},
+		{Skin{Priority: PriorityWatching}, " [Watching]"},
+	}
+	for _, test := range tests {
+		if got := wishlistDecoration(test.skin); got != test.want {
+			t.Errorf("wishlistDecoration(%+v) = %q, want %q", test.skin, got, test.want)
+		}
+	}
+}
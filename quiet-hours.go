@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+func (quietHours QuietHours) Validate() error {
+	if quietHours.Start == "" && quietHours.End == "" {
+		return nil
+	}
+	if _, err := parseClock(quietHours.Start); err != nil {
+		return err
+	}
+	_, err := parseClock(quietHours.End)
+	return err
+}
+
+func (quietHours QuietHours) EndsAt(now time.Time) (time.Time, bool) {
+	start, err := parseClock(quietHours.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := parseClock(quietHours.End)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	elapsed := now.Sub(midnight)
+	switch {
+	case start < end && elapsed >= start && elapsed < end:
+		return midnight.Add(end), true
+	case start > end && elapsed >= start:
+		return midnight.AddDate(0, 0, 1).Add(end), true
+	case start > end && elapsed < end:
+		return midnight.Add(end), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursEndsAt(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 19, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		quietHours QuietHours
+		now        time.Time
+		want       time.Time
+		wantQuiet  bool
+	}{
+		{"daytime inside", QuietHours{Start: "09:00", End: "17:30"}, day(12, 0), day(17, 30), true},
+		{"daytime at start", QuietHours{Start: "09:00", End: "17:30"}, day(9, 0), day(17, 30), true},
+		{"daytime at end", QuietHours{Start: "09:00", End: "17:30"}, day(17, 30), time.Time{}, false},
+		{"daytime before", QuietHours{Start: "09:00", End: "17:30"}, day(8, 59), time.Time{}, false},
+		{"overnight before midnight", QuietHours{Start: "22:00", End: "07:00"}, day(23, 15), day(7, 0).AddDate(0, 0, 1), true},
+		{"overnight after midnight", QuietHours{Start: "22:00", End: "07:00"}, day(3, 0), day(7, 0), true},
+		{"overnight outside", QuietHours{Start: "22:00", End: "07:00"}, day(12, 0), time.Time{}, false},
+		{"same start and end", QuietHours{Start: "08:00", End: "08:00"}, day(8, 0), time.Time{}, false},
+		{"disabled", QuietHours{}, day(12, 0), time.Time{}, false},
+		{"invalid", QuietHours{Start: "25:00", End: "07:00"}, day(3, 0), time.Time{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, quiet := test.quietHours.EndsAt(test.now)
+			if quiet != test.wantQuiet || !got.Equal(test.want) {
+				t.Errorf("EndsAt(%v) = %v, %v, want %v, %v", test.now, got, quiet, test.want, test.wantQuiet)
+			}
+		})
+	}
+}
+
+func TestQuietHoursValidate(t *testing.T) {
+	tests := []struct {
+		quietHours QuietHours
+		wantErr    bool
+	}{
+		{QuietHours{}, false},
+		{QuietHours{Start: "22:00", End: " 07:00 "}, false},
+		{QuietHours{Start: "22:00"}, true},
+		{QuietHours{Start: "10pm", End: "07:00"}, true},
+		{QuietHours{Start: "22:00", End: "24:00"}, true},
+	}
+	for _, test := range tests {
+		if err := test.quietHours.Validate(); (err != nil) != test.wantErr {
+			t.Errorf("Validate(%+v) error = %v, want error %v", test.quietHours, err, test.wantErr)
+		}
+	}
+}
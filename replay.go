@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+type FixtureRequest struct {
+	Method string      `json:"method"`
+	Url    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+type FixtureResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type recordingTransport struct {
+	next  http.RoundTripper
+	dir   string
+	mutex sync.Mutex
+	count int
+}
+
+type replayingTransport struct {
+	mutex    sync.Mutex
+	fixtures map[string][]Fixture
+}
+
+var redactedHeaders = []string{"Authorization", "X-Riot-Entitlements-JWT", "X-Riot-Token", "Cookie", "Set-Cookie"}
+
+var redactedBodyPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`"password"\s*:\s*"[^"]*"`), `"password":"` + redacted + `"`},
+	{regexp.MustCompile(`"entitlements_token"\s*:\s*"[^"]*"`), `"entitlements_token":"` + redacted + `"`},
+	{regexp.MustCompile(`(access_token|id_token)=[^&"#]+`), `$1=` + redacted},
+}
+
+var fixtureNameCleaner = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		values := header[http.CanonicalHeaderKey(name)]
+		for i, value := range values {
+			if name == "Authorization" {
+				scheme, _, _ := strings.Cut(value, " ")
+				values[i] = scheme + " " + redacted
+			} else if name == "Cookie" || name == "Set-Cookie" {
+				values[i] = redactCookie(value, name == "Set-Cookie")
+			} else {
+				values[i] = redacted
+			}
+		}
+	}
+	return header
+}
+
+func redactCookie(cookie string, onlyFirst bool) string {
+	parts := strings.Split(cookie, ";")
+	for i, part := range parts {
+		if onlyFirst && i > 0 {
+			break
+		}
+		if key, _, found := strings.Cut(part, "="); found {
+			parts[i] = key + "=" + redacted
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+func redactBody(body string) string {
+	for _, rule := range redactedBodyPatterns {
+		body = rule.pattern.ReplaceAllString(body, rule.replacement)
+	}
+	return body
+}
+
+func fixtureKey(method string, url string) string {
+	return method + " " + redactBody(url)
+}
+
+func newRecordingTransport(next http.RoundTripper, dir string) (*recordingTransport, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &recordingTransport{next: next, dir: dir}, nil
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		if requestBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(responseBody))
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			Url:    redactBody(req.URL.String()),
+			Header: redactHeader(req.Header),
+			Body:   redactBody(string(requestBody)),
+		},
+		Response: FixtureResponse{
+			StatusCode: res.StatusCode,
+			Header:     redactHeader(res.Header),
+			Body:       redactBody(string(responseBody)),
+		},
+	}
+	if err := t.save(fixture, req); err != nil {
+		log.Printf("the fixture for %s could not be saved: %v", fixtureKey(req.Method, req.URL.String()), err)
+	}
+	return res, nil
+}
+
+func (t *recordingTransport) save(fixture Fixture, req *http.Request) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	t.count++
+	fileName := fmt.Sprintf("%04d-%s-%s.json", t.count, req.Method, strings.Trim(fixtureNameCleaner.ReplaceAllString(req.URL.Host+req.URL.Path, "_"), "_"))
+	t.mutex.Unlock()
+	return os.WriteFile(filepath.Join(t.dir, fileName), data, 0644)
+}
+
+func newReplayingTransport(dir string) (*replayingTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	sort.Strings(files)
+	t := &replayingTransport{fixtures: make(map[string][]Fixture)}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		key := fixtureKey(fixture.Request.Method, fixture.Request.Url)
+		t.fixtures[key] = append(t.fixtures[key], fixture)
+	}
+	return t, nil
+}
+
+func (t *replayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	key := fixtureKey(req.Method, req.URL.String())
+	t.mutex.Lock()
+	queue := t.fixtures[key]
+	if len(queue) == 0 {
+		t.mutex.Unlock()
+		return nil, fmt.Errorf("no recorded fixture for %s", key)
+	}
+	fixture := queue[0]
+	if len(queue) > 1 {
+		t.fixtures[key] = queue[1:]
+	}
+	t.mutex.Unlock()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fixture.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(fixture.Response.Body)),
+		ContentLength: int64(len(fixture.Response.Body)),
+		Request:       req,
+	}, nil
+}
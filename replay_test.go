@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"password":"hunter2"}` {
+			t.Errorf("body = %s", body)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "asid", Value: "session"})
+		w.Write([]byte(`{"uri":"https://playvalorant.com/opt_in#access_token=secret&id_token=secret"}`))
+	}))
+	defer server.Close()
+	tests := []struct {
+		name      string
+		removeDir bool
+		wantFiles int
+	}{
+		{"recorded", false, 1},
+		{"fixture directory removed", true, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "fixtures")
+			transport, err := newRecordingTransport(http.DefaultTransport, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.removeDir {
+				os.RemoveAll(dir)
+			}
+			req, _ := http.NewRequest("PUT", server.URL+"/api/v1/authorization", strings.NewReader(`{"password":"hunter2"}`))
+			req.Header.Set("Authorization", "Bearer secret")
+			res, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			body, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			if !strings.Contains(string(body), "access_token=secret") {
+				t.Errorf("response body = %s", body)
+			}
+			files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+			if len(files) != test.wantFiles {
+				t.Fatalf("recorded %v, want %d fixtures", files, test.wantFiles)
+			}
+			if test.wantFiles == 0 {
+				return
+			}
+			data, _ := os.ReadFile(files[0])
+			if strings.Contains(string(data), "secret") || strings.Contains(string(data), "hunter2") {
+				t.Errorf("fixture %s leaks a secret: %s", files[0], data)
+			}
+		})
+	}
+}
+
+func TestReplayingTransport(t *testing.T) {
+	transport, err := newReplayingTransport(filepath.Join("testdata", "replay", "login"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method     string
+		url        string
+		wantStatus int
+		wantErr    bool
+	}{
+		{"POST", "https://entitlements.auth.riotgames.com/api/token/v1", 401, false},
+		{"POST", "https://entitlements.auth.riotgames.com/api/token/v1", 200, false},
+		{"POST", "https://entitlements.auth.riotgames.com/api/token/v1", 200, false},
+		{"GET", "https://valorant-api.com/v1/bundles/unknown", 0, true},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		res, err := transport.RoundTrip(req)
+		if (err != nil) != test.wantErr {
+			t.Errorf("RoundTrip(%s %s) error = %v", test.method, test.url, err)
+			continue
+		}
+		if err == nil && res.StatusCode != test.wantStatus {
+			t.Errorf("RoundTrip(%s %s) status = %d, want %d", test.method, test.url, res.StatusCode, test.wantStatus)
+		}
+	}
+	if _, err := newReplayingTransport(t.TempDir()); err == nil {
+		t.Error("newReplayingTransport() of an empty directory did not fail")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func newRotationSummary(snapshot ShopSnapshot) Notification {
+	snapshot.Wishlist = nil
+	return Notification{
+		Title:   tr("Your Valorant shop of %s", time.Now().Format("2006-01-02")),
+		Message: rotationSummaryText(snapshot),
+		Account: snapshot.Account,
+		Summary: &snapshot,
+	}
+}
+
+func rotationSummaryText(snapshot ShopSnapshot) string {
+	hits := make(map[string]bool)
+	for _, match := range snapshot.Matches {
+		hits[match.LevelId] = true
+	}
+	var daily, nightMarket []string
+	for _, offer := range snapshot.Offers {
+		line := "• " + localizedName(offer.Skin, locale)
+		if offer.Price > 0 {
+			line += fmt.Sprintf(" - %d VP", offer.Price)
+		}
+		if offer.DiscountPercent > 0 {
+			line += fmt.Sprintf(" (-%d%%)", offer.DiscountPercent)
+		}
+		if hits[offer.LevelId] {
+			line += " ⭐"
+		}
+		if offer.NightMarket {
+			nightMarket = append(nightMarket, line)
+		} else {
+			daily = append(daily, line)
+		}
+	}
+	lines := daily
+	if len(nightMarket) > 0 {
+		lines = append(lines, "", tr("Night market:"))
+		lines = append(lines, nightMarket...)
+	}
+	for _, bundle := range snapshot.Bundles {
+		line := tr("Bundle: %s", bundle.Name)
+		if bundle.Price > 0 {
+			line += fmt.Sprintf(" - %d VP", bundle.Price)
+		}
+		lines = append(lines, "", line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newShopDigest(snapshot ShopSnapshot) Notification {
+	digest := newRotationSummary(snapshot)
+	digest.Digest = true
+	return digest
+}
+
+func wantsBundles() bool {
+	summary := Notification{Summary: &ShopSnapshot{}}
+	digest := Notification{Summary: &ShopSnapshot{}, Digest: true}
+	for _, notifier := range globalStore.Notifiers {
+		if acceptsNotification(notifier, summary) || acceptsNotification(notifier, digest) {
+			return true
+		}
+	}
+	return false
+}
+
+func sendRotationSummary(snapshot ShopSnapshot) []error {
+	if snapshot.Rotation == "" {
+		return nil
+	}
+	errs := deliverNotification(appContext, newRotationSummary(snapshot))
+	return append(errs, deliverNotification(appContext, newShopDigest(snapshot))...)
+}
@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+func TestRotationSummaryText(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot ShopSnapshot
+		want     string
+	}{
+		{
+			"daily shop with a wishlist hit",
+			ShopSnapshot{
+				Offers:  []Offer{{LevelId: "1", Skin: Skin{Name: "Prime Vandal"}, Price: 1775}, {LevelId: "2", Skin: Skin{Name: "Reaver Sheriff"}, Price: 2175}},
+				Matches: []Match{{LevelId: "2"}},
+			},
+			"• Prime Vandal - 1775 VP\n• Reaver Sheriff - 2175 VP ⭐",
+		},
+		{
+			"night market and bundles",
+			ShopSnapshot{
+				Offers:  []Offer{{LevelId: "1", Skin: Skin{Name: "Prime Vandal"}}, {LevelId: "2", Skin: Skin{Name: "Ion Phantom"}, Price: 1065, DiscountPercent: 40, NightMarket: true}},
+				Bundles: []Bundle{{Name: "Prime", Price: 7100}, {Name: "Unpriced"}},
+			},
+			"• Prime Vandal\n\nNight market:\n• Ion Phantom - 1065 VP (-40%)\n\nBundle: Prime - 7100 VP\n\nBundle: Unpriced",
+		},
+		{"empty shop", ShopSnapshot{}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := rotationSummaryText(test.snapshot); got != test.want {
+				t.Errorf("rotationSummaryText() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewRotationSummary(t *testing.T) {
+	snapshot := ShopSnapshot{Account: "player", Rotation: "2026-10-20T00:00:00Z", Wishlist: []Skin{{Name: "Prime Vandal"}}}
+	notification := newRotationSummary(snapshot)
+	if notification.Account != "player" || notification.Summary == nil || notification.Summary.Rotation != snapshot.Rotation {
+		t.Fatalf("newRotationSummary() = %+v", notification)
+	}
+	if notification.Summary.Wishlist != nil {
+		t.Errorf("summary wishlist = %v, want none", notification.Summary.Wishlist)
+	}
+}
+
+func TestWantsBundles(t *testing.T) {
+	tests := []struct {
+		name            string
+		rotationSummary bool
+		notifiers       []Notifier
+		want            bool
+	}{
+		{"no channels", true, nil, false},
+		{"rotation summary off", false, []Notifier{&fakeNotifier{name: "fake"}}, false},
+		{"rotation summary on", true, []Notifier{&fakeNotifier{name: "fake"}}, true},
+		{"match command", true, []Notifier{&commandNotifier{settings: CommandSettings{On: commandOnMatch}}}, false},
+		{"rotation command", false, []Notifier{&commandNotifier{settings: CommandSettings{On: commandOnRotation}}}, true},
+		{"instant email", false, []Notifier{&smtpNotifier{settings: SMTPSettings{Mode: emailModeInstant}}}, false},
+		{"email digest", false, []Notifier{&smtpNotifier{settings: SMTPSettings{Mode: emailModeDigest}}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useTestOutbox(t, test.notifiers...)
+			globalStore.Settings.Notifications.RotationSummary = test.rotationSummary
+			if got := wantsBundles(); got != test.want {
+				t.Errorf("wantsBundles() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/Knetic/govaluate.v3"
+)
+
+type WatchRule struct {
+	Name       string   `json:"name"`
+	Expression string   `json:"expression"`
+	Priority   Priority `json:"priority,omitempty"`
+	MaxPrice   int      `json:"maxPrice,omitempty"`
+	expression *govaluate.EvaluableExpression
+}
+
+type SkinAttributes struct {
+	Weapon     string
+	Tier       string
+	Collection string
+}
+
+type Offer struct {
+	LevelId         string
+	Skin            Skin
+	Price           int
+	BasePrice       int
+	DiscountPercent int
+	NightMarket     bool
+	Attributes      SkinAttributes
+	ExpiresAt       time.Time
+}
+
+type WeaponsResponse struct {
+	Data []struct {
+		DisplayName string `json:"displayName"`
+		Skins       []struct {
+			Uuid            string `json:"uuid"`
+			ThemeUuid       string `json:"themeUuid"`
+			ContentTierUuid string `json:"contentTierUuid"`
+			Levels          []struct {
+				Uuid string `json:"uuid"`
+			} `json:"levels"`
+		} `json:"skins"`
+	} `json:"data"`
+}
+
+type NamedEntitiesResponse struct {
+	Data []struct {
+		Uuid        string `json:"uuid"`
+		DisplayName string `json:"displayName"`
+		DevName     string `json:"devName"`
+	} `json:"data"`
+}
+
+var ruleVariables = []string{"name", "weapon", "tier", "collection", "price", "basePrice", "discount", "nightMarket"}
+
+var ruleFunctions = map[string]govaluate.ExpressionFunction{
+	"contains": func(arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 2 {
+			return nil, errors.New("contains expects 2 arguments")
+		}
+		return strings.Contains(strings.ToLower(fmt.Sprint(arguments[0])), strings.ToLower(fmt.Sprint(arguments[1]))), nil
+	},
+	"lower": func(arguments ...interface{}) (interface{}, error) {
+		if len(arguments) != 1 {
+			return nil, errors.New("lower expects 1 argument")
+		}
+		return strings.ToLower(fmt.Sprint(arguments[0])), nil
+	},
+}
+
+var skinAttributesCache struct {
+	sync.Mutex
+	byId map[string]SkinAttributes
+}
+
+func (rule WatchRule) Compile() (*govaluate.EvaluableExpression, error) {
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(rule.Expression, ruleFunctions)
+	if err != nil {
+		return nil, err
+	}
+	for _, variable := range expression.Vars() {
+		known := false
+		for _, ruleVariable := range ruleVariables {
+			known = known || variable == ruleVariable
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown variable %q, available variables are %s", variable, strings.Join(ruleVariables, ", "))
+		}
+	}
+	return expression, nil
+}
+
+func (rule *WatchRule) precompile() error {
+	expression, err := rule.Compile()
+	if err != nil {
+		return err
+	}
+	rule.expression = expression
+	return nil
+}
+
+func (rule WatchRule) Matches(offer Offer) (bool, error) {
+	if rule.expression == nil {
+		if err := rule.precompile(); err != nil {
+			return false, err
+		}
+	}
+	result, err := rule.expression.Evaluate(offer.RuleParameters())
+	if err != nil {
+		return false, err
+	}
+	matches, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("rule %q does not evaluate to true or false", rule.Name)
+	}
+	return matches, nil
+}
+
+func (offer Offer) RuleParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        offer.Skin.Name,
+		"weapon":      offer.Attributes.Weapon,
+		"tier":        offer.Attributes.Tier,
+		"collection":  offer.Attributes.Collection,
+		"price":       float64(offer.Price),
+		"basePrice":   float64(offer.BasePrice),
+		"discount":    float64(offer.DiscountPercent),
+		"nightMarket": offer.NightMarket,
+	}
+}
+
+func (rule WatchRule) String() string {
+	return rule.Name + ": " + rule.Expression + " [" + rule.Priority.Label() + "]"
+}
+
+func fetchNamedEntities(ctx context.Context, url string, useDevName bool) (map[string]string, error) {
+	req, _ := http.NewRequest("GET", url, nil)
+	var response NamedEntitiesResponse
+	if err := doRequest(ctx, req, &response); err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, entity := range response.Data {
+		names[strings.ToLower(entity.Uuid)] = entity.DisplayName
+		if useDevName && entity.DevName != "" {
+			names[strings.ToLower(entity.Uuid)] = entity.DevName
+		}
+	}
+	return names, nil
+}
+
+func fetchSkinAttributes(ctx context.Context) (map[string]SkinAttributes, error) {
+	skinAttributesCache.Lock()
+	byId := skinAttributesCache.byId
+	skinAttributesCache.Unlock()
+	if byId != nil {
+		return byId, nil
+	}
+	byId, err := downloadSkinAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	skinAttributesCache.Lock()
+	defer skinAttributesCache.Unlock()
+	if skinAttributesCache.byId == nil {
+		skinAttributesCache.byId = byId
+	}
+	return skinAttributesCache.byId, nil
+}
+
+func downloadSkinAttributes(ctx context.Context) (map[string]SkinAttributes, error) {
+	tiers, err := fetchNamedEntities(ctx, "https://valorant-api.com/v1/contenttiers", true)
+	if err != nil {
+		return nil, err
+	}
+	themes, err := fetchNamedEntities(ctx, "https://valorant-api.com/v1/themes", false)
+	if err != nil {
+		return nil, err
+	}
+	req, _ := http.NewRequest("GET", "https://valorant-api.com/v1/weapons", nil)
+	var weapons WeaponsResponse
+	if err := doRequest(ctx, req, &weapons); err != nil {
+		return nil, err
+	}
+	byId := make(map[string]SkinAttributes)
+	for _, weapon := range weapons.Data {
+		for _, skin := range weapon.Skins {
+			attributes := SkinAttributes{
+				Weapon:     weapon.DisplayName,
+				Tier:       tiers[strings.ToLower(skin.ContentTierUuid)],
+				Collection: themes[strings.ToLower(skin.ThemeUuid)],
+			}
+			byId[strings.ToLower(skin.Uuid)] = attributes
+			for _, level := range skin.Levels {
+				byId[strings.ToLower(level.Uuid)] = attributes
+			}
+		}
+	}
+	return byId, nil
+}
+
+func findMatches(wishlist []Skin, rules []WatchRule, offers []Offer) ([]Match, []error) {
+	var errs []error
+	matchesByOffer := make(map[string]Match)
+	var order []string
+	add := func(match Match) {
+		if !match.IsWithinBudget() {
+			return
+		}
+		key := match.LevelId
+		if existing, ok := matchesByOffer[key]; ok {
+			if existing.Priority.Urgency() >= match.Priority.Urgency() {
+				return
+			}
+		} else {
+			order = append(order, key)
+		}
+		matchesByOffer[key] = match
+	}
+	var compiledRules []WatchRule
+	for _, rule := range rules {
+		if rule.expression == nil {
+			if err := rule.precompile(); err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, err))
+				continue
+			}
+		}
+		compiledRules = append(compiledRules, rule)
+	}
+	for _, offer := range offers {
+		for _, skin := range wishlist {
+			if offer.Skin.Id != "" && skin.Id == offer.Skin.Id {
+				add(Match{LevelId: offer.LevelId, Skin: offer.Skin, Priority: skin.Priority.OrDefault(), MaxPrice: skin.MaxPrice, NightMarket: offer.NightMarket, ExpiresAt: offer.ExpiresAt})
+			}
+		}
+		for _, rule := range compiledRules {
+			matches, err := rule.Matches(offer)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, err))
+				continue
+			}
+			if matches {
+				add(Match{LevelId: offer.LevelId, Skin: offer.Skin, Priority: rule.Priority.OrDefault(), MaxPrice: rule.MaxPrice, NightMarket: offer.NightMarket, ExpiresAt: offer.ExpiresAt, Rule: rule.Name})
+			}
+		}
+	}
+	matches := make([]Match, 0, len(order))
+	for _, key := range order {
+		matches = append(matches, matchesByOffer[key])
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Priority.Urgency() > matches[j].Priority.Urgency()
+	})
+	return matches, errs
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWatchRuleCompile(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"weapon == 'Vandal' && tier == 'Exclusive'", ""},
+		{"contains(name, 'prime') || lower(collection) == 'reaver'", ""},
+		{"nightMarket && discount >= 35", ""},
+		{"rarity == 'Exclusive'", "unknown variable \"rarity\""},
+		{"weapon ==", "Unexpected end of expression"},
+	}
+	for _, test := range tests {
+		_, err := WatchRule{Expression: test.expression}.Compile()
+		if test.wantErr == "" && err != nil || test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+			t.Errorf("Compile(%q) error = %v, want %q", test.expression, err, test.wantErr)
+		}
+	}
+}
+
+func TestWatchRuleMatches(t *testing.T) {
+	offer := Offer{
+		Skin:            Skin{Name: "Prime Vandal"},
+		Price:           1100,
+		BasePrice:       1775,
+		DiscountPercent: 38,
+		NightMarket:     true,
+		Attributes:      SkinAttributes{Weapon: "Vandal", Tier: "Premium", Collection: "Prime"},
+	}
+	tests := []struct {
+		expression string
+		want       bool
+		wantErr    bool
+	}{
+		{"weapon == 'Vandal' && collection == 'Prime'", true, false},
+		{"tier == 'Exclusive'", false, false},
+		{"nightMarket && discount >= 35", true, false},
+		{"price < basePrice", true, false},
+		{"contains(name, 'PRIME')", true, false},
+		{"lower(weapon) == 'vandal'", true, false},
+		{"price + 1", false, true},
+	}
+	for _, test := range tests {
+		got, err := WatchRule{Name: "rule", Expression: test.expression}.Matches(offer)
+		if got != test.want || (err != nil) != test.wantErr {
+			t.Errorf("Matches(%q) = %v, %v, want %v", test.expression, got, err, test.want)
+		}
+	}
+}
+
+func TestWatchRuleMatchesWithoutAttributes(t *testing.T) {
+	offer := Offer{Skin: Skin{Name: "Prime Vandal"}, Price: 1775}
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"weapon == 'Vandal'", false},
+		{"contains(name, 'vandal')", true},
+		{"price > 1000", true},
+	}
+	for _, test := range tests {
+		got, err := WatchRule{Expression: test.expression}.Matches(offer)
+		if err != nil || got != test.want {
+			t.Errorf("Matches(%q) = %v, %v, want %v", test.expression, got, err, test.want)
+		}
+	}
+}
+
+func TestFindMatches(t *testing.T) {
+	offers := []Offer{
+		{LevelId: "1", Skin: Skin{Id: "a", Name: "Prime Vandal", Price: 1775}, Price: 1775, Attributes: SkinAttributes{Weapon: "Vandal"}},
+		{LevelId: "2", Skin: Skin{Id: "b", Name: "Reaver Sheriff", Price: 2175}, Price: 2175, Attributes: SkinAttributes{Weapon: "Sheriff"}},
+		{LevelId: "3", Skin: Skin{Id: "c", Name: "Ion Phantom", Price: 1775}, Price: 1775, Attributes: SkinAttributes{Weapon: "Phantom"}},
+	}
+	wishlist := []Skin{{Id: "a", Priority: PriorityWatching}, {Id: "b", Priority: PriorityMustHave, MaxPrice: 1000}}
+	rules := []WatchRule{
+		{Name: "vandals", Expression: "weapon == 'Vandal'", Priority: PriorityMustHave},
+		{Name: "phantoms", Expression: "weapon == 'Phantom'"},
+		{Name: "broken", Expression: "price + 1"},
+	}
+	matches, errs := findMatches(wishlist, rules, offers)
+	if len(errs) != len(offers) {
+		t.Errorf("findMatches() errors = %v, want one per offer", errs)
+	}
+	var got []string
+	for _, match := range matches {
+		got = append(got, match.LevelId+":"+string(match.Priority)+":"+match.Rule)
+	}
+	want := []string{"1:must-have:vandals", "3:nice-to-have:phantoms"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("findMatches() = %v, want %v", got, want)
+	}
+}
+
+func TestWatchRuleIsCompiledOnce(t *testing.T) {
+	rule := WatchRule{Name: "vandals", Expression: "weapon == 'Vandal'"}
+	if err := rule.precompile(); err != nil {
+		t.Fatal(err)
+	}
+	rule.Expression = "weapon =="
+	matches, err := rule.Matches(Offer{Attributes: SkinAttributes{Weapon: "Vandal"}})
+	if err != nil || !matches {
+		t.Errorf("Matches() = %v, %v, want the precompiled expression to be used", matches, err)
+	}
+	offers := []Offer{{LevelId: "1"}, {LevelId: "2"}, {LevelId: "3"}}
+	if _, errs := findMatches(nil, []WatchRule{{Name: "broken", Expression: "weapon =="}}, offers); len(errs) != 1 {
+		t.Errorf("findMatches() errors = %v, want one for the invalid rule", errs)
+	}
+}
+
+func TestFetchSkinAttributes(t *testing.T) {
+	skinAttributesCache.byId = nil
+	t.Cleanup(func() { skinAttributesCache.byId = nil })
+	var requests int32
+	stubClient(t, func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		switch req.URL.Path {
+		case "/v1/contenttiers":
+			return stubResponse(200, `{"data":[{"uuid":"T","displayName":"Premium Edition","devName":"Premium"}]}`), nil
+		case "/v1/themes":
+			return stubResponse(200, `{"data":[{"uuid":"TH","displayName":"Prime"}]}`), nil
+		case "/v1/weapons":
+			return stubResponse(200, `{"data":[{"displayName":"Vandal","skins":[{"uuid":"S","themeUuid":"th","contentTierUuid":"t","levels":[{"uuid":"L"}]}]}]}`), nil
+		}
+		return stubResponse(404, `{}`), nil
+	})
+	for i := 0; i < 2; i++ {
+		attributes, err := fetchSkinAttributes(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := SkinAttributes{Weapon: "Vandal", Tier: "Premium", Collection: "Prime"}
+		if attributes["s"] != want || attributes["l"] != want {
+			t.Errorf("fetchSkinAttributes() = %+v, want %+v for the skin and its level", attributes, want)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("fetchSkinAttributes() made %d requests, want 3", requests)
+	}
+}
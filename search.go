@@ -0,0 +1,246 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const minSearchScore = 0.45
+
+var weaponKeywords = map[string][]string{
+	"classic":  {"Classic"},
+	"shorty":   {"Shorty"},
+	"frenzy":   {"Frenzy"},
+	"ghost":    {"Ghost"},
+	"sheriff":  {"Sheriff"},
+	"stinger":  {"Stinger"},
+	"spectre":  {"Spectre"},
+	"bucky":    {"Bucky"},
+	"judge":    {"Judge"},
+	"bulldog":  {"Bulldog"},
+	"guardian": {"Guardian"},
+	"phantom":  {"Phantom"},
+	"vandal":   {"Vandal"},
+	"marshal":  {"Marshal"},
+	"outlaw":   {"Outlaw"},
+	"operator": {"Operator"},
+	"ares":     {"Ares"},
+	"odin":     {"Odin"},
+	"melee":    {"Melee"},
+	"knife":    {"Melee"},
+	"sidearm":  {"Classic", "Shorty", "Frenzy", "Ghost", "Sheriff"},
+	"pistol":   {"Classic", "Shorty", "Frenzy", "Ghost", "Sheriff"},
+	"smg":      {"Stinger", "Spectre"},
+	"shotgun":  {"Bucky", "Judge"},
+	"rifle":    {"Bulldog", "Guardian", "Phantom", "Vandal"},
+	"sniper":   {"Marshal", "Outlaw", "Operator"},
+	"mg":       {"Ares", "Odin"},
+	"heavy":    {"Ares", "Odin"},
+}
+
+type SearchResult struct {
+	Skin  Skin
+	Score float64
+}
+
+type searchEntry struct {
+	skin   Skin
+	weapon string
+	names  [][]string
+}
+
+type SkinSearchIndex struct {
+	entries []searchEntry
+}
+
+var searchNormalizer = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+func normalizeSearchText(text string) string {
+	normalized, _, err := transform.String(searchNormalizer, text)
+	if err != nil {
+		normalized = text
+	}
+	return strings.ToLower(normalized)
+}
+
+func searchTokens(text string) []string {
+	return strings.FieldsFunc(normalizeSearchText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func skinWeapon(skin Skin, attributes map[string]SkinAttributes) string {
+	if weapon := attributes[strings.ToLower(skin.Id)].Weapon; weapon != "" {
+		return weapon
+	}
+	for _, token := range searchTokens(skin.Name) {
+		if weapons, ok := weaponKeywords[token]; ok && len(weapons) == 1 {
+			return weapons[0]
+		}
+	}
+	return ""
+}
+
+func newSkinSearchIndex(skins []Skin, attributes map[string]SkinAttributes) *SkinSearchIndex {
+	index := &SkinSearchIndex{entries: make([]searchEntry, 0, len(skins))}
+	for _, skin := range skins {
+		entry := searchEntry{skin: skin, weapon: skinWeapon(skin, attributes)}
+		seen := make(map[string]bool)
+		addName := func(name string) {
+			tokens := searchTokens(name)
+			key := strings.Join(tokens, " ")
+			if len(tokens) > 0 && !seen[key] {
+				seen[key] = true
+				entry.names = append(entry.names, tokens)
+			}
+		}
+		addName(skin.Name)
+		if skin.LocalizedNames.Map != nil {
+			skin.LocalizedNames.Range(func(_ any, value any) bool {
+				addName(value.(string))
+				return true
+			})
+		}
+		index.entries = append(index.entries, entry)
+	}
+	return index
+}
+
+func (index *SkinSearchIndex) Search(query string, limit int) []SearchResult {
+	queryTokens := searchTokens(query)
+	if len(queryTokens) == 0 {
+		return nil
+	}
+	var results []SearchResult
+	for _, entry := range index.entries {
+		if score := entry.score(queryTokens); score >= minSearchScore {
+			results = append(results, SearchResult{Skin: entry.skin, Score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return len(results[i].Skin.Name) < len(results[j].Skin.Name)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func (entry searchEntry) score(queryTokens []string) float64 {
+	best := 0.0
+	for _, nameTokens := range entry.names {
+		total := 0.0
+		for _, queryToken := range queryTokens {
+			tokenScore := entry.weaponScore(queryToken)
+			for _, nameToken := range nameTokens {
+				if score := tokenMatchScore(queryToken, nameToken); score > tokenScore {
+					tokenScore = score
+				}
+			}
+			if tokenScore == 0 {
+				total = 0
+				break
+			}
+			total += tokenScore
+		}
+		score := total / float64(len(queryTokens))
+		if score > 0 {
+			score -= 0.01 * float64(len(nameTokens)-len(queryTokens))
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+func (entry searchEntry) weaponScore(queryToken string) float64 {
+	if entry.weapon == "" {
+		return 0
+	}
+	best := 0.0
+	for keyword, weapons := range weaponKeywords {
+		score := tokenMatchScore(queryToken, keyword)
+		if score <= best {
+			continue
+		}
+		for _, weapon := range weapons {
+			if weapon == entry.weapon {
+				best = score
+			}
+		}
+	}
+	return best
+}
+
+func tokenMatchScore(queryToken string, nameToken string) float64 {
+	switch {
+	case queryToken == nameToken:
+		return 1
+	case strings.HasPrefix(nameToken, queryToken):
+		return 0.9
+	case len([]rune(queryToken)) >= 3 && strings.Contains(nameToken, queryToken):
+		return 0.7
+	}
+	queryLength := len([]rune(queryToken))
+	allowed := 0
+	switch {
+	case queryLength >= 8:
+		allowed = 2
+	case queryLength >= 4:
+		allowed = 1
+	}
+	if allowed == 0 {
+		return 0
+	}
+	distance := editDistance(queryToken, nameToken)
+	if prefix := []rune(nameToken); len(prefix) > queryLength {
+		if prefixDistance := editDistance(queryToken, string(prefix[:queryLength])); prefixDistance < distance {
+			distance = prefixDistance
+		}
+	}
+	if distance > allowed {
+		return 0
+	}
+	return 0.6 - 0.1*float64(distance-1)
+}
+
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous2 := make([]int, len(target)+1)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+			if i > 1 && j > 1 && source[i-1] == target[j-2] && source[i-2] == target[j-1] {
+				current[j] = minInt(current[j], previous2[j-2]+1)
+			}
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+	return previous[len(target)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
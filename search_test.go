@@ -0,0 +1,105 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"", "", 0},
+		{"vandal", "", 6},
+		{"", "vandal", 6},
+		{"vandal", "vandal", 0},
+		{"vandl", "vandal", 1},
+		{"vnadal", "vandal", 1},
+		{"phantom", "fantom", 2},
+		{"kitten", "sitting", 3},
+		{"élan", "elan", 1},
+		{"ca", "abc", 3},
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b); got != test.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestTokenMatchScore(t *testing.T) {
+	tests := []struct {
+		query string
+		name  string
+		want  float64
+	}{
+		{"prime", "prime", 1},
+		{"pri", "prime", 0.9},
+		{"and", "vandal", 0.7},
+		{"an", "vandal", 0},
+		{"vandl", "vandal", 0.6},
+		{"oprator", "operator", 0.6},
+		{"operatro", "operator", 0.6},
+		{"oparetor", "operator", 0.5},
+		{"xyz", "vandal", 0},
+	}
+	for _, test := range tests {
+		if got := tokenMatchScore(test.query, test.name); got < test.want-1e-9 || got > test.want+1e-9 {
+			t.Errorf("tokenMatchScore(%q, %q) = %v, want %v", test.query, test.name, got, test.want)
+		}
+	}
+}
+
+func TestSkinSearchIndexSearch(t *testing.T) {
+	localized := func(name string, translations ...string) Skin {
+		skin := Skin{Id: name, Name: name, LocalizedNames: SynchronizedMap{&sync.Map{}}}
+		for i := 0; i+1 < len(translations); i += 2 {
+			skin.LocalizedNames.Store(translations[i], translations[i+1])
+		}
+		return skin
+	}
+	skins := []Skin{
+		localized("Prime Vandal", "fr-FR", "Vandal Prime"),
+		localized("Prime Phantom"),
+		localized("Reaver Operator"),
+		localized("Élan Sheriff"),
+		localized("Oni Claw", "fr-FR", "Griffe Oni"),
+	}
+	attributes := map[string]SkinAttributes{"oni claw": {Weapon: "Melee"}}
+	index := newSkinSearchIndex(skins, attributes)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"prime vandl", []string{"Prime Vandal"}},
+		{"PRIME", []string{"Prime Vandal", "Prime Phantom"}},
+		{"sniper", []string{"Reaver Operator"}},
+		{"elan", []string{"Élan Sheriff"}},
+		{"griffe", []string{"Oni Claw"}},
+		{"oni knife", []string{"Oni Claw"}},
+		{"", nil},
+		{"zzzz", nil},
+	}
+	for _, test := range tests {
+		results := index.Search(test.query, 0)
+		var got []string
+		for _, result := range results {
+			got = append(got, result.Skin.Name)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("Search(%q) = %v, want %v", test.query, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Search(%q) = %v, want %v", test.query, got, test.want)
+				break
+			}
+		}
+	}
+	if results := index.Search("prime", 1); len(results) != 1 {
+		t.Errorf("Search() with a limit returned %d results", len(results))
+	}
+}
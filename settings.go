@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+const settingsFile = "settings.json"
+
+type NetworkSettings struct {
+	Proxy       string                `json:"proxy"`
+	CACertFiles []string              `json:"caCertFiles"`
+	TLSProfile  string                `json:"tlsProfile"`
+	TLSProfiles map[string]TLSProfile `json:"tlsProfiles,omitempty"`
+}
+
+type DiscordSettings struct {
+	WebhookURL         string            `json:"webhookUrl"`
+	AccountWebhookURLs map[string]string `json:"accountWebhookUrls,omitempty"`
+}
+
+type NotificationSettings struct {
+	QuietHours      QuietHours        `json:"quietHours"`
+	RotationSummary bool              `json:"rotationSummary"`
+	URLs            []string          `json:"urls"`
+	Discord         DiscordSettings   `json:"discord"`
+	Email           SMTPSettings      `json:"email"`
+	Telegram        TelegramSettings  `json:"telegram"`
+	Webhooks        []WebhookSettings `json:"webhooks"`
+	Commands        []CommandSettings `json:"commands"`
+	Ntfy            NtfySettings      `json:"ntfy"`
+	Gotify          GotifySettings    `json:"gotify"`
+}
+
+type Settings struct {
+	Language         string               `json:"language,omitempty"`
+	FallbackLanguage string               `json:"fallbackLanguage,omitempty"`
+	Network          NetworkSettings      `json:"network"`
+	Notifications    NotificationSettings `json:"notifications"`
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		Network: NetworkSettings{
+			Proxy:       "environment",
+			CACertFiles: []string{},
+			TLSProfile:  defaultTLSProfile,
+		},
+		Notifications: NotificationSettings{
+			URLs:     []string{},
+			Webhooks: []WebhookSettings{},
+			Commands: []CommandSettings{},
+		},
+	}
+}
+
+func loadSettings() (Settings, error) {
+	settings := defaultSettings()
+	data, err := os.ReadFile(statePath(settingsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, saveSettings(settings)
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return defaultSettings(), err
+	}
+	return settings, nil
+}
+
+func saveSettings(settings Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(statePath(settingsFile), data)
+}
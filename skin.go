@@ -1,196 +1,222 @@
 package main
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/base64"
-	"encoding/json"
-	"io/ioutil"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
 	"net/http"
-	"net/http/cookiejar"
 	"os"
-
-	"github.com/lxn/walk"
+	"strings"
+	"sync"
+	"time"
 )
 
-var cj, _ = cookiejar.New(nil)
-var defaultTransport = http.DefaultTransport.(*http.Transport)
-var customTransport = &http.Transport{
-	Proxy:                 defaultTransport.Proxy,
-	DialContext:           defaultTransport.DialContext,
-	MaxIdleConns:          defaultTransport.MaxIdleConns,
-	IdleConnTimeout:       defaultTransport.IdleConnTimeout,
-	ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
-	TLSHandshakeTimeout:   defaultTransport.TLSHandshakeTimeout,
-	TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12, MaxVersion: tls.VersionTLS12, PreferServerCipherSuites: false,
-		CipherSuites: []uint16{
-			tls.TLS_CHACHA20_POLY1305_SHA256,
-			tls.TLS_AES_128_GCM_SHA256,
-			tls.TLS_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
-			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
-			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
-		}},
-}
-
-var client = &http.Client{Jar: cj, Transport: customTransport}
+const savedSkinsFile = "skins.json"
 
-func fetchSkins() ([]Skin, error) {
+func fetchSkins(ctx context.Context) ([]Skin, error) {
 	req, _ := http.NewRequest("GET", "https://eu.api.riotgames.com/val/content/v1/contents?locale="+locale, nil)
 	apiKey, err := base64.StdEncoding.DecodeString("UkdBUEktYmRiMThjY2MtYmE2My00MjFiLTk3MGEtYjM4NjEzOTdjMjY4")
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("X-Riot-Token", string(apiKey))
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
 	var response Response
-	err = json.NewDecoder(res.Body).Decode(&response)
-	if err != nil {
+	if err := doRequest(ctx, req, &response); err != nil {
 		return nil, err
 	}
 	return response.Skins, nil
 }
 
 func loadSavedSkins() {
-	file, _ := os.ReadFile("saves/skins.json")
-	var savedSkins SortedSkins
-	err := json.Unmarshal(file, &savedSkins)
+	savedSkinsPath := statePath(savedSkinsFile)
+	file, err := os.ReadFile(savedSkinsPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
 	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not load saved skins", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		showError(tr("The app could not load saved skins"), err)
+		return
+	}
+	document, version, err := decodeWishlist(file)
+	if err != nil {
+		keptPath := savedSkinsPath + ".invalid-" + time.Now().Format("20060102-150405")
+		if renameErr := os.Rename(savedSkinsPath, keptPath); renameErr != nil {
+			showError(tr("The app could not load saved skins"), fmt.Errorf("%w (and could not set the file aside: %v)", err, renameErr))
+			return
+		}
+		showError(tr("The app could not load saved skins, the original file was kept as %s", keptPath), err)
+		return
+	}
+	var savedSkins SortedSkins
+	for _, wishlistSkin := range document.Skins {
+		savedSkins = append(savedSkins, wishlistSkin.Skin())
 	}
 	globalStore.Ui.selectedSkinsListBox.AllSkins = savedSkins
+	globalStore.Rules = document.Rules
+	if version < wishlistVersion {
+		if err := writeFileAtomic(fmt.Sprintf("%s.v%d.bak", savedSkinsPath, version), file); err != nil {
+			showError(tr("The app could not back up saved skins before upgrading them"), err)
+			return
+		}
+		saveSkinsData()
+	}
 }
 
-func feedData() {
-	res, err := fetchSkins()
+func feedData(ctx context.Context) {
+	res, err := fetchSkins(ctx)
 	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not fetch skins", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		showError(tr("The app could not fetch skins"), err)
 	}
 	globalStore.Ui.skinsListBox.FeedList(res)
+	attributes, _ := fetchSkinAttributes(ctx)
+	globalStore.SearchIndex = newSkinSearchIndex(globalStore.Ui.skinsListBox.AllSkins, attributes)
 }
 
 func saveSkinsData() {
-	json, err := json.MarshalIndent(globalStore.Ui.selectedSkinsListBox.AllSkins, "", "  ")
+	data, err := encodeWishlist(globalStore.Ui.selectedSkinsListBox.AllSkins, globalStore.Rules)
 	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not save the data", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		showError(tr("The app could not save the data"), err)
+		return
 	}
-	if _, err := os.Stat("saves"); os.IsNotExist(err) {
-		os.Mkdir("saves", 0777)
-	}
-	err = ioutil.WriteFile("saves/skins.json", json, 0644)
+	err = writeFileWithBackups(statePath(savedSkinsFile), data)
 	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not save the data", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		showError(tr("The app could not save the data"), err)
+	}
+}
+
+func getOffer(ctx context.Context, levelId string, accessToken string, entitlementsToken string, attributes map[string]SkinAttributes) (Offer, error) {
+	req, _ := http.NewRequest("GET", "https://valorant-api.com/v1/weapons/skinlevels/"+levelId, nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Riot-Entitlements-JWT", entitlementsToken)
+	var skinDataResponse SkinDataResponse
+	if err := doRequest(ctx, req, &skinDataResponse); err != nil {
+		return Offer{}, err
+	}
+	offer := Offer{LevelId: levelId, Attributes: attributes[strings.ToLower(levelId)]}
+	for _, skin := range globalStore.Ui.skinsListBox.AllSkins {
+		if skin.Name == skinDataResponse.Data.DisplayName {
+			offer.Skin = skin
+			break
+		}
 	}
+	if offer.Skin.Name == "" {
+		offer.Skin = Skin{Name: skinDataResponse.Data.DisplayName, LocalizedNames: SynchronizedMap{&sync.Map{}}}
+		offer.Skin.LocalizedNames.Store("en-US", skinDataResponse.Data.DisplayName)
+	}
+	offer.Skin.Video = skinDataResponse.Data.StreamedVideo
+	return offer, nil
 }
 
-func getSkinsInShop(shop Shop, accessToken string, entitlementsToken string) ([]Skin, error) {
-	var skinsInShopNameVideos []SkinNameVideo
-	var skinsInShop []Skin
-	for _, tmpSkin := range shop.SkinsPanelLayout.SingleItemOffers {
-		req, _ := http.NewRequest("GET", "https://valorant-api.com/v1/weapons/skinlevels/"+tmpSkin, nil)
-		req.Header.Set("Authorization", "Bearer "+accessToken)
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("X-Riot-Entitlements-JWT", entitlementsToken)
-		res, err := client.Do(req)
+func getOffers(ctx context.Context, shop Shop, accessToken string, entitlementsToken string) ([]Offer, error) {
+	attributes, err := fetchSkinAttributes(ctx)
+	if err != nil {
+		log.Printf("watch rules are evaluated without skin attributes: %v", err)
+	}
+	prices := make(map[string]int)
+	for _, storeOffer := range shop.SkinsPanelLayout.SingleItemStoreOffers {
+		prices[storeOffer.OfferID] = storeOffer.Cost[valorantPointsId]
+	}
+	now := time.Now()
+	dailyExpiresAt := now.Add(time.Duration(shop.SkinsPanelLayout.SingleItemOffersRemainingDurationInSeconds) * time.Second)
+	nightMarketExpiresAt := now.Add(time.Duration(shop.BonusStore.BonusStoreRemainingDurationInSeconds) * time.Second)
+	var offers []Offer
+	for _, levelId := range shop.SkinsPanelLayout.SingleItemOffers {
+		offer, err := getOffer(ctx, levelId, accessToken, entitlementsToken, attributes)
 		if err != nil {
 			return nil, err
 		}
-		defer res.Body.Close()
-		var skinDataResponse SkinDataResponse
-		err = json.NewDecoder(res.Body).Decode(&skinDataResponse)
+		offer.Price = prices[levelId]
+		offer.BasePrice = offer.Price
+		offer.Skin.Price = offer.Price
+		offer.ExpiresAt = dailyExpiresAt
+		offers = append(offers, offer)
+	}
+	for _, bonusOffer := range shop.BonusStore.BonusStoreOffers {
+		offer, err := getOffer(ctx, bonusOffer.Offer.OfferID, accessToken, entitlementsToken, attributes)
 		if err != nil {
 			return nil, err
 		}
-		skinsInShopNameVideos = append(skinsInShopNameVideos, SkinNameVideo{Name: skinDataResponse.Data.DisplayName, Video: skinDataResponse.Data.StreamedVideo})
+		offer.Price = bonusOffer.DiscountCosts[valorantPointsId]
+		offer.BasePrice = bonusOffer.Offer.Cost[valorantPointsId]
+		offer.DiscountPercent = bonusOffer.DiscountPercent
+		offer.NightMarket = true
+		offer.Skin.Price = offer.Price
+		offer.ExpiresAt = nightMarketExpiresAt
+		offers = append(offers, offer)
 	}
-	for _, skin := range globalStore.Ui.skinsListBox.AllSkins {
-		for _, skinObject := range skinsInShopNameVideos {
-			if skinObject.Name == skin.Name {
-				skin.Video = skinObject.Video
-				skinsInShop = append(skinsInShop, skin)
-			}
+	return offers, nil
+}
+
+func getBundles(ctx context.Context, shop Shop) []Bundle {
+	now := time.Now()
+	var bundles []Bundle
+	for _, storeBundle := range shop.FeaturedBundle.Bundles {
+		req, _ := http.NewRequest("GET", "https://valorant-api.com/v1/bundles/"+storeBundle.DataAssetID, nil)
+		var bundleDataResponse BundleDataResponse
+		name := storeBundle.DataAssetID
+		if err := doRequest(ctx, req, &bundleDataResponse); err != nil {
+			log.Printf("bundle %s is listed without its name: %v", storeBundle.DataAssetID, err)
+		} else if bundleDataResponse.Data.DisplayName != "" {
+			name = bundleDataResponse.Data.DisplayName
 		}
+		price := storeBundle.TotalDiscountedCost[valorantPointsId]
+		if price == 0 {
+			price = storeBundle.TotalBaseCost[valorantPointsId]
+		}
+		bundles = append(bundles, Bundle{
+			Id:        storeBundle.DataAssetID,
+			Name:      name,
+			Price:     price,
+			ExpiresAt: now.Add(time.Duration(storeBundle.DurationRemainingInSeconds) * time.Second),
+		})
 	}
-	return skinsInShop, nil
+	return bundles
 }
 
-func fetchSkinsWithToken(accessToken string) ([]Skin, error) {
+func dailySkins(offers []Offer) []Skin {
+	var skins []Skin
+	for _, offer := range offers {
+		if !offer.NightMarket {
+			skins = append(skins, offer.Skin)
+		}
+	}
+	return skins
+}
+
+func fetchSkinsWithToken(ctx context.Context, accessToken string) ([]Offer, []Bundle, error) {
 	req, _ := http.NewRequest("POST", "https://entitlements.auth.riotgames.com/api/token/v1", nil)
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
 	var entitlementResponse EntitlementResponse
-	json.NewDecoder(res.Body).Decode(&entitlementResponse)
+	if err := doIdempotentRequest(ctx, req, &entitlementResponse); err != nil {
+		return nil, nil, err
+	}
 	req, _ = http.NewRequest("POST", "https://auth.riotgames.com/userinfo", nil)
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Riot-Entitlements-JWT", entitlementResponse.EntitlementsToken)
-	res, err = client.Do(req)
-	if err != nil {
-		walk.MsgBox(nil, "Error", "Couldn't fetch account information", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
-	}
-	defer res.Body.Close()
 	var userId UserId
-	json.NewDecoder(res.Body).Decode(&userId)
+	if err := doIdempotentRequest(ctx, req, &userId); err != nil {
+		return nil, nil, err
+	}
 	req, _ = http.NewRequest("GET", "https://pd."+globalStore.User.Region+".a.pvp.net/store/v2/storefront/"+userId.Sub, nil)
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Riot-Entitlements-JWT", entitlementResponse.EntitlementsToken)
-	res, err = client.Do(req)
-	if err != nil {
-		walk.MsgBox(nil, "Error", "Couldn't fetch account information", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
-	}
-	defer res.Body.Close()
 	var shop Shop
-	err = json.NewDecoder(res.Body).Decode(&shop)
-	if err != nil {
-		walk.MsgBox(nil, "Error", "Couldn't fetch store information", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+	if err := doRequest(ctx, req, &shop); err != nil {
+		return nil, nil, err
 	}
-	skinsInShop, err := getSkinsInShop(shop, accessToken, entitlementResponse.EntitlementsToken)
+	offers, err := getOffers(ctx, shop, accessToken, entitlementResponse.EntitlementsToken)
 	if err != nil {
-		walk.MsgBox(nil, "Error", "Couldn't fetch skins", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		return nil, nil, err
+	}
+	if !wantsBundles() {
+		return offers, nil, nil
 	}
-	return skinsInShop, nil
+	return offers, getBundles(ctx, shop), nil
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetOffersWithoutSkinAttributes(t *testing.T) {
+	skinAttributesCache.byId = nil
+	t.Cleanup(func() { skinAttributesCache.byId = nil })
+	stubClient(t, func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Path, "/v1/weapons/skinlevels/") {
+			id := strings.TrimPrefix(req.URL.Path, "/v1/weapons/skinlevels/")
+			return stubResponse(200, `{"data":{"uuid":"`+id+`","displayName":"Skin `+id+`"}}`), nil
+		}
+		return stubResponse(404, `{}`), nil
+	})
+	var shop Shop
+	shop.SkinsPanelLayout.SingleItemOffers = []string{"a"}
+	shop.SkinsPanelLayout.SingleItemStoreOffers = []StoreOffer{{OfferID: "a", Cost: map[string]int{valorantPointsId: 1775}}}
+	shop.BonusStore.BonusStoreOffers = []BonusStoreOffer{{DiscountPercent: 40, DiscountCosts: map[string]int{valorantPointsId: 1065}}}
+	shop.BonusStore.BonusStoreOffers[0].Offer.OfferID = "b"
+	shop.BonusStore.BonusStoreOffers[0].Offer.Cost = map[string]int{valorantPointsId: 1775}
+
+	offers, err := getOffers(context.Background(), shop, "access", "entitlements")
+	if err != nil {
+		t.Fatalf("getOffers() error = %v", err)
+	}
+	if len(offers) != 2 || offers[0].Skin.Name != "Skin a" || offers[0].Price != 1775 || !offers[1].NightMarket || offers[1].Price != 1065 || offers[1].BasePrice != 1775 {
+		t.Fatalf("getOffers() = %+v", offers)
+	}
+	matches, errs := findMatches(nil, []WatchRule{{Name: "discounts", Expression: "discount >= 35 && weapon == ''"}}, offers)
+	if len(errs) != 0 || len(matches) != 1 || matches[0].LevelId != "b" {
+		t.Errorf("findMatches() = %+v, %v", matches, errs)
+	}
+}
+
+func TestGetBundles(t *testing.T) {
+	stubClient(t, func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/v1/bundles/named" {
+			return stubResponse(200, `{"data":{"uuid":"named","displayName":"Prime"}}`), nil
+		}
+		return stubResponse(404, `{}`), nil
+	})
+	var shop Shop
+	shop.FeaturedBundle.Bundles = []StoreBundle{
+		{DataAssetID: "named", TotalBaseCost: map[string]int{valorantPointsId: 8700}, TotalDiscountedCost: map[string]int{valorantPointsId: 7100}, DurationRemainingInSeconds: 3600},
+		{DataAssetID: "missing", TotalBaseCost: map[string]int{valorantPointsId: 5000}},
+	}
+	bundles := getBundles(context.Background(), shop)
+	if len(bundles) != 2 {
+		t.Fatalf("getBundles() = %+v", bundles)
+	}
+	tests := []struct {
+		bundle    Bundle
+		wantName  string
+		wantPrice int
+	}{
+		{bundles[0], "Prime", 7100},
+		{bundles[1], "missing", 5000},
+	}
+	for _, test := range tests {
+		if test.bundle.Name != test.wantName || test.bundle.Price != test.wantPrice {
+			t.Errorf("bundle = %+v, want %s for %d VP", test.bundle, test.wantName, test.wantPrice)
+		}
+	}
+	if until := time.Until(bundles[0].ExpiresAt); until < 59*time.Minute || until > time.Hour {
+		t.Errorf("bundle expires in %v", until)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+const snoozesFile = "snoozes.json"
+
+type Snoozes struct {
+	mutex    sync.Mutex
+	path     string
+	Skins    map[string]time.Time `json:"skins"`
+	Accounts map[string]time.Time `json:"accounts"`
+}
+
+func newSnoozes(path string) *Snoozes {
+	return &Snoozes{path: path, Skins: make(map[string]time.Time), Accounts: make(map[string]time.Time)}
+}
+
+func loadSnoozes(path string) (*Snoozes, error) {
+	snoozes := newSnoozes(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return snoozes, nil
+	}
+	if err != nil {
+		return snoozes, err
+	}
+	if err := json.Unmarshal(data, snoozes); err != nil {
+		return newSnoozes(path), err
+	}
+	if snoozes.Skins == nil {
+		snoozes.Skins = make(map[string]time.Time)
+	}
+	if snoozes.Accounts == nil {
+		snoozes.Accounts = make(map[string]time.Time)
+	}
+	return snoozes, nil
+}
+
+func snoozeUntil(entries map[string]time.Time, key string, until time.Time) {
+	if until.After(time.Now()) {
+		entries[key] = until
+	} else {
+		delete(entries, key)
+	}
+}
+
+func (snoozes *Snoozes) SnoozeSkin(id string, until time.Time) error {
+	snoozes.mutex.Lock()
+	snoozeUntil(snoozes.Skins, id, until)
+	snoozes.mutex.Unlock()
+	return snoozes.Save()
+}
+
+func (snoozes *Snoozes) SnoozeAccount(account string, until time.Time) error {
+	snoozes.mutex.Lock()
+	snoozeUntil(snoozes.Accounts, account, until)
+	snoozes.mutex.Unlock()
+	return snoozes.Save()
+}
+
+func (snoozes *Snoozes) SkinSnoozedUntil(id string) time.Time {
+	snoozes.mutex.Lock()
+	defer snoozes.mutex.Unlock()
+	return snoozes.Skins[id]
+}
+
+func (snoozes *Snoozes) AccountSnoozedUntil(account string) time.Time {
+	snoozes.mutex.Lock()
+	defer snoozes.mutex.Unlock()
+	return snoozes.Accounts[account]
+}
+
+func (snoozes *Snoozes) IsSnoozed(notification Notification, now time.Time) bool {
+	return now.Before(snoozes.SkinSnoozedUntil(notification.Match.Skin.Id)) || now.Before(snoozes.AccountSnoozedUntil(notification.Account))
+}
+
+func (snoozes *Snoozes) Save() error {
+	snoozes.mutex.Lock()
+	defer snoozes.mutex.Unlock()
+	now := time.Now()
+	for _, entries := range []map[string]time.Time{snoozes.Skins, snoozes.Accounts} {
+		for key, until := range entries {
+			if !until.After(now) {
+				delete(entries, key)
+			}
+		}
+	}
+	data, err := json.MarshalIndent(snoozes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(snoozes.path, data)
+}
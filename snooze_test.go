@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnoozesIsSnoozed(t *testing.T) {
+	now := time.Now()
+	snoozes := newSnoozes(filepath.Join(t.TempDir(), snoozesFile))
+	if err := snoozes.SnoozeSkin("SKIN", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := snoozes.SnoozeAccount("player", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		notification Notification
+		at           time.Time
+		want         bool
+	}{
+		{"snoozed skin", Notification{Account: "other", Match: Match{Skin: Skin{Id: "SKIN"}}}, now, true},
+		{"snoozed account", Notification{Account: "player", Match: Match{Skin: Skin{Id: "OTHER"}}}, now, true},
+		{"not snoozed", Notification{Account: "other", Match: Match{Skin: Skin{Id: "OTHER"}}}, now, false},
+		{"snooze over", Notification{Account: "player", Match: Match{Skin: Skin{Id: "SKIN"}}}, now.Add(2 * time.Hour), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := snoozes.IsSnoozed(test.notification, test.at); got != test.want {
+				t.Errorf("IsSnoozed() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSnoozesPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), snoozesFile)
+	now := time.Now()
+	snoozes, err := loadSnoozes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snoozes.SnoozeSkin("SKIN", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := snoozes.SnoozeAccount("player", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	snoozes.Skins["EXPIRED"] = now.Add(-time.Minute)
+	if err := snoozes.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadSnoozes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.SkinSnoozedUntil("SKIN").IsZero() || reloaded.AccountSnoozedUntil("player").IsZero() {
+		t.Errorf("reloaded snoozes = %+v", reloaded)
+	}
+	if _, ok := reloaded.Skins["EXPIRED"]; ok {
+		t.Error("expired snooze was saved")
+	}
+	if err := reloaded.SnoozeSkin("SKIN", now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.SkinSnoozedUntil("SKIN").IsZero() {
+		t.Error("snoozing until the past did not clear the snooze")
+	}
+}
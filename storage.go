@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	appDirName         = "ValorantShopwatcher"
+	portableDirName    = "saves"
+	portableMarkerFile = "portable"
+	stateBackups       = 3
+)
+
+var stateDir string
+
+func resolveStateDir(portable bool) (string, error) {
+	appExec, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if appExecResolved, err := filepath.EvalSymlinks(appExec); err == nil {
+		appExec = appExecResolved
+	}
+	appDir := filepath.Dir(appExec)
+	if !portable {
+		if _, err := os.Stat(filepath.Join(appDir, portableMarkerFile)); err == nil {
+			portable = true
+		}
+	}
+	if portable {
+		return filepath.Join(appDir, portableDirName), nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appDirName), nil
+}
+
+func setupStateDir(portable bool) error {
+	dir, err := resolveStateDir(portable)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	stateDir = dir
+	return importLegacyState()
+}
+
+func statePath(name string) string {
+	return filepath.Join(stateDir, name)
+}
+
+func importLegacyState() error {
+	var legacyDirs []string
+	if workingDir, err := os.Getwd(); err == nil {
+		legacyDirs = append(legacyDirs, filepath.Join(workingDir, portableDirName))
+	}
+	if appExec, err := os.Executable(); err == nil {
+		legacyDirs = append(legacyDirs, filepath.Join(filepath.Dir(appExec), portableDirName))
+	}
+	for _, name := range []string{savedSkinsFile, settingsFile} {
+		if _, err := os.Stat(statePath(name)); !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		for _, legacyDir := range legacyDirs {
+			legacyPath := filepath.Join(legacyDir, name)
+			if sameFile(legacyPath, statePath(name)) {
+				continue
+			}
+			data, err := os.ReadFile(legacyPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := writeFileAtomic(statePath(name), data); err != nil {
+				return fmt.Errorf("importing %s: %w", legacyPath, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func sameFile(path1 string, path2 string) bool {
+	absolute1, err1 := filepath.Abs(path1)
+	absolute2, err2 := filepath.Abs(path2)
+	return err1 == nil && err2 == nil && absolute1 == absolute2
+}
+
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
+
+func rotateBackups(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	os.Remove(backupPath(path, stateBackups))
+	for index := stateBackups - 1; index >= 1; index-- {
+		if err := os.Rename(backupPath(path, index), backupPath(path, index+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return copyFile(path, backupPath(path, 1))
+}
+
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(destination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	return writeFile(path, data, false)
+}
+
+func writeFileWithBackups(path string, data []byte) error {
+	return writeFile(path, data, true)
+}
+
+func writeFile(path string, data []byte, backups bool) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if backups {
+		if err := rotateBackups(path); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+func syncDir(dir string) {
+	if handle, err := os.Open(dir); err == nil {
+		handle.Sync()
+		handle.Close()
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "settings.json")
+	for _, content := range []string{"first", "second"} {
+		if err := writeFileAtomic(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readTestFile(t, path); got != "second" {
+		t.Errorf("content = %q, want second", got)
+	}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	if len(files) != 1 {
+		t.Errorf("files = %v, want only %s without backups or temporary files", files, path)
+	}
+}
+
+func TestWriteFileWithBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), savedSkinsFile)
+	for version := 1; version <= 5; version++ {
+		if err := writeFileWithBackups(path, []byte(strconv.Itoa(version))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path, "5"},
+		{backupPath(path, 1), "4"},
+		{backupPath(path, 2), "3"},
+		{backupPath(path, 3), "2"},
+	}
+	for _, test := range tests {
+		if got := readTestFile(t, test.path); got != test.want {
+			t.Errorf("%s = %q, want %q", filepath.Base(test.path), got, test.want)
+		}
+	}
+	if _, err := os.Stat(backupPath(path, stateBackups+1)); !os.IsNotExist(err) {
+		t.Errorf("backup %d exists, want only %d backups", stateBackups+1, stateBackups)
+	}
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	if len(files) != 0 {
+		t.Errorf("temporary files left behind: %v", files)
+	}
+}
+
+func TestImportLegacyState(t *testing.T) {
+	workingDir := t.TempDir()
+	previousWorkingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(workingDir); err != nil {
+		t.Fatal(err)
+	}
+	previousStateDir := stateDir
+	t.Cleanup(func() {
+		os.Chdir(previousWorkingDir)
+		stateDir = previousStateDir
+	})
+	legacyDir := filepath.Join(workingDir, portableDirName)
+	if err := os.Mkdir(legacyDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{savedSkinsFile: "legacy skins", settingsFile: "legacy settings"} {
+		if err := os.WriteFile(filepath.Join(legacyDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name         string
+		stateDir     string
+		existing     map[string]string
+		wantSkins    string
+		wantSettings string
+	}{
+		{"imports missing files", filepath.Join(t.TempDir(), appDirName), nil, "legacy skins", "legacy settings"},
+		{"keeps existing files", filepath.Join(t.TempDir(), appDirName), map[string]string{settingsFile: "current settings"}, "legacy skins", "current settings"},
+		{"state directory is the legacy one", legacyDir, nil, "legacy skins", "legacy settings"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stateDir = test.stateDir
+			if err := os.MkdirAll(stateDir, 0700); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range test.existing {
+				if err := os.WriteFile(statePath(name), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := importLegacyState(); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, statePath(savedSkinsFile)); got != test.wantSkins {
+				t.Errorf("%s = %q, want %q", savedSkinsFile, got, test.wantSkins)
+			}
+			if got := readTestFile(t, statePath(settingsFile)); got != test.wantSettings {
+				t.Errorf("%s = %q, want %q", settingsFile, got, test.wantSettings)
+			}
+		})
+	}
+}
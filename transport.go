@@ -0,0 +1,239 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultTLSProfile = "riot-client"
+
+type TLSProfile struct {
+	MinVersion       string   `json:"minVersion"`
+	MaxVersion       string   `json:"maxVersion"`
+	CipherSuites     []string `json:"cipherSuites"`
+	CurvePreferences []string `json:"curvePreferences"`
+}
+
+var builtinTLSProfiles = map[string]TLSProfile{
+	"riot-client": {
+		MinVersion: "1.2",
+		MaxVersion: "1.2",
+		CipherSuites: []string{
+			"TLS_CHACHA20_POLY1305_SHA256",
+			"TLS_AES_128_GCM_SHA256",
+			"TLS_AES_256_GCM_SHA384",
+			"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+			"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+			"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+			"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+			"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+			"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+			"TLS_RSA_WITH_AES_128_GCM_SHA256",
+			"TLS_RSA_WITH_AES_256_GCM_SHA384",
+			"TLS_RSA_WITH_AES_128_CBC_SHA",
+			"TLS_RSA_WITH_AES_256_CBC_SHA",
+		},
+	},
+	"go-default": {
+		MinVersion: "1.2",
+		MaxVersion: "1.3",
+	},
+	"tls13-only": {
+		MinVersion: "1.3",
+		MaxVersion: "1.3",
+	},
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsCurves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+var cj, _ = cookiejar.New(nil)
+var defaultTransport = http.DefaultTransport.(*http.Transport)
+var customTransport = newTransport(http.ProxyFromEnvironment, nil)
+
+var client = &http.Client{Jar: cj, Transport: customTransport, Timeout: time.Minute}
+
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+func newTransport(proxy func(*http.Request) (*url.URL, error), tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           defaultTransport.DialContext,
+		MaxIdleConns:          defaultTransport.MaxIdleConns,
+		IdleConnTimeout:       defaultTransport.IdleConnTimeout,
+		ExpectContinueTimeout: defaultTransport.ExpectContinueTimeout,
+		TLSHandshakeTimeout:   defaultTransport.TLSHandshakeTimeout,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+func (profile TLSProfile) Config(rootCAs *x509.CertPool) (*tls.Config, error) {
+	config := &tls.Config{RootCAs: rootCAs, PreferServerCipherSuites: false}
+	var ok bool
+	if profile.MinVersion != "" {
+		if config.MinVersion, ok = tlsVersions[profile.MinVersion]; !ok {
+			return nil, fmt.Errorf("unknown TLS version %q", profile.MinVersion)
+		}
+	}
+	if profile.MaxVersion != "" {
+		if config.MaxVersion, ok = tlsVersions[profile.MaxVersion]; !ok {
+			return nil, fmt.Errorf("unknown TLS version %q", profile.MaxVersion)
+		}
+	}
+	for _, name := range profile.CipherSuites {
+		id, ok := cipherSuiteID(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+	for _, name := range profile.CurvePreferences {
+		curve, ok := tlsCurves[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown curve %q", name)
+		}
+		config.CurvePreferences = append(config.CurvePreferences, curve)
+	}
+	return config, nil
+}
+
+func tlsProfileNames(custom map[string]TLSProfile) []string {
+	var names []string
+	for name := range builtinTLSProfiles {
+		if _, overridden := custom[name]; !overridden {
+			names = append(names, name)
+		}
+	}
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseProxy(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	switch strings.ToLower(proxy) {
+	case "", "environment":
+		return http.ProxyFromEnvironment, nil
+	case "direct", "none":
+		return nil, nil
+	}
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+	switch proxyUrl.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https or socks5", proxyUrl.Scheme)
+	}
+	if proxyUrl.Host == "" {
+		return nil, fmt.Errorf("proxy %s has no host", redactNotificationURL(proxy))
+	}
+	return http.ProxyURL(proxyUrl), nil
+}
+
+func loadRootCAs(files []string) (*x509.CertPool, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%s does not contain any PEM certificate", file)
+		}
+	}
+	return pool, nil
+}
+
+func buildTransport(network NetworkSettings) (*http.Transport, error) {
+	proxy, err := parseProxy(network.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+	rootCAs, err := loadRootCAs(network.CACertFiles)
+	if err != nil {
+		return nil, fmt.Errorf("CA certificates: %w", err)
+	}
+	profileName := network.TLSProfile
+	if profileName == "" {
+		profileName = defaultTLSProfile
+	}
+	profile, ok := network.TLSProfiles[profileName]
+	if !ok {
+		if profile, ok = builtinTLSProfiles[profileName]; !ok {
+			return nil, fmt.Errorf("unknown TLS profile %q, available profiles are %s", profileName, strings.Join(tlsProfileNames(network.TLSProfiles), ", "))
+		}
+	}
+	tlsConfig, err := profile.Config(rootCAs)
+	if err != nil {
+		return nil, fmt.Errorf("TLS profile %q: %w", profileName, err)
+	}
+	return newTransport(proxy, tlsConfig), nil
+}
+
+func setupTransport(network NetworkSettings, recordDir string, replayDir string) error {
+	if replayDir != "" {
+		transport, err := newReplayingTransport(replayDir)
+		if err != nil {
+			return err
+		}
+		client.Transport = transport
+		return nil
+	}
+	transport, err := buildTransport(network)
+	if err != nil {
+		if transport, defaultErr := buildTransport(NetworkSettings{}); defaultErr == nil {
+			customTransport = transport
+			client.Transport = customTransport
+		}
+		return err
+	}
+	customTransport = transport
+	client.Transport = customTransport
+	if recordDir != "" {
+		transport, err := newRecordingTransport(customTransport, recordDir)
+		if err != nil {
+			return err
+		}
+		client.Transport = transport
+	}
+	return nil
+}
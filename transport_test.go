@@ -0,0 +1,181 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTLSProfileConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     TLSProfile
+		wantMin     uint16
+		wantMax     uint16
+		wantCiphers int
+		wantErr     string
+	}{
+		{"riot-client", builtinTLSProfiles["riot-client"], tls.VersionTLS12, tls.VersionTLS12, 17, ""},
+		{"go-default", builtinTLSProfiles["go-default"], tls.VersionTLS12, tls.VersionTLS13, 0, ""},
+		{"tls13-only", builtinTLSProfiles["tls13-only"], tls.VersionTLS13, tls.VersionTLS13, 0, ""},
+		{"empty", TLSProfile{}, 0, 0, 0, ""},
+		{"unknown minimum version", TLSProfile{MinVersion: "1.4"}, 0, 0, 0, `unknown TLS version "1.4"`},
+		{"unknown maximum version", TLSProfile{MaxVersion: "2"}, 0, 0, 0, `unknown TLS version "2"`},
+		{"unknown cipher suite", TLSProfile{CipherSuites: []string{"TLS_NULL"}}, 0, 0, 0, `unknown cipher suite "TLS_NULL"`},
+		{"unknown curve", TLSProfile{CurvePreferences: []string{"P224"}}, 0, 0, 0, `unknown curve "P224"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := test.profile.Config(nil)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("Config() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.MinVersion != test.wantMin || config.MaxVersion != test.wantMax || len(config.CipherSuites) != test.wantCiphers {
+				t.Errorf("Config() = min %x max %x %d ciphers, want min %x max %x %d ciphers", config.MinVersion, config.MaxVersion, len(config.CipherSuites), test.wantMin, test.wantMax, test.wantCiphers)
+			}
+		})
+	}
+	config, err := TLSProfile{CipherSuites: []string{"TLS_RSA_WITH_AES_128_CBC_SHA"}, CurvePreferences: []string{"X25519", "P256"}}.Config(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CipherSuites[0] != tls.TLS_RSA_WITH_AES_128_CBC_SHA || len(config.CurvePreferences) != 2 || config.CurvePreferences[0] != tls.X25519 {
+		t.Errorf("Config() = %+v", config)
+	}
+}
+
+func TestParseProxy(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://valorant-api.com/v1/weapons", nil)
+	tests := []struct {
+		proxy   string
+		wantNil bool
+		wantURL string
+		wantErr string
+	}{
+		{"direct", true, "", ""},
+		{"None", true, "", ""},
+		{"http://proxy.lan:3128", false, "http://proxy.lan:3128", ""},
+		{"https://proxy.lan", false, "https://proxy.lan", ""},
+		{"socks5://127.0.0.1:1080", false, "socks5://127.0.0.1:1080", ""},
+		{"ftp://proxy.lan", false, "", `unsupported proxy scheme "ftp"`},
+		{"proxy.lan:3128", false, "", "unsupported proxy scheme"},
+		{"http://", false, "", "has no host"},
+		{"http://proxy.lan:%zz", false, "", "invalid"},
+	}
+	for _, test := range tests {
+		t.Run(test.proxy, func(t *testing.T) {
+			proxy, err := parseProxy(test.proxy)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("parseProxy() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (proxy == nil) != test.wantNil {
+				t.Fatalf("parseProxy() returned nil %v, want %v", proxy == nil, test.wantNil)
+			}
+			if proxy == nil {
+				return
+			}
+			if proxyURL, err := proxy(req); err != nil || proxyURL.String() != test.wantURL {
+				t.Errorf("proxy = %v, %v, want %s", proxyURL, err, test.wantURL)
+			}
+		})
+	}
+	for _, proxy := range []string{"", "environment"} {
+		if got, err := parseProxy(proxy); got == nil || err != nil {
+			t.Errorf("parseProxy(%q) error = %v, want the environment proxy", proxy, err)
+		}
+	}
+}
+
+func TestBuildTransport(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not-pem.crt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		network NetworkSettings
+		wantMin uint16
+		wantMax uint16
+		wantErr string
+	}{
+		{"default", NetworkSettings{}, tls.VersionTLS12, tls.VersionTLS12, ""},
+		{"built-in profile", NetworkSettings{TLSProfile: "tls13-only"}, tls.VersionTLS13, tls.VersionTLS13, ""},
+		{"custom profile", NetworkSettings{TLSProfile: "legacy", TLSProfiles: map[string]TLSProfile{"legacy": {MinVersion: "1.0"}}}, tls.VersionTLS10, 0, ""},
+		{"custom profile overrides a built-in one", NetworkSettings{TLSProfiles: map[string]TLSProfile{"riot-client": {MaxVersion: "1.3"}}}, 0, tls.VersionTLS13, ""},
+		{"unknown profile", NetworkSettings{TLSProfile: "chrome"}, 0, 0, `unknown TLS profile "chrome", available profiles are go-default, riot-client, tls13-only`},
+		{"invalid profile", NetworkSettings{TLSProfile: "broken", TLSProfiles: map[string]TLSProfile{"broken": {MinVersion: "9"}}}, 0, 0, `TLS profile "broken": unknown TLS version "9"`},
+		{"invalid proxy", NetworkSettings{Proxy: "ftp://proxy.lan"}, 0, 0, "proxy: "},
+		{"missing CA bundle", NetworkSettings{CACertFiles: []string{filepath.Join(dir, "missing.crt")}}, 0, 0, "CA certificates: "},
+		{"CA bundle without certificates", NetworkSettings{CACertFiles: []string{notPEM}}, 0, 0, "does not contain any PEM certificate"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport, err := buildTransport(test.network)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("buildTransport() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config := transport.TLSClientConfig; config.MinVersion != test.wantMin || config.MaxVersion != test.wantMax {
+				t.Errorf("TLS versions = %x-%x, want %x-%x", config.MinVersion, config.MaxVersion, test.wantMin, test.wantMax)
+			}
+		})
+	}
+}
+
+func TestBuildTransportTrustsCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	bundle := filepath.Join(t.TempDir(), "ca.crt")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, files := range [][]string{nil, {bundle}} {
+		transport, err := buildTransport(NetworkSettings{Proxy: "direct", TLSProfile: "go-default", CACertFiles: files})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err == nil {
+			res.Body.Close()
+		}
+		if (err == nil) != (files != nil) {
+			t.Errorf("GET with CA files %v error = %v", files, err)
+		}
+	}
+}
+
+func TestSetupTransportKeepsTheDefaultProfileOnError(t *testing.T) {
+	previousTransport, previousCustom := client.Transport, customTransport
+	t.Cleanup(func() { client.Transport, customTransport = previousTransport, previousCustom })
+	if err := setupTransport(NetworkSettings{TLSProfile: "chrome"}, "", ""); err == nil {
+		t.Fatal("setupTransport() with an unknown profile did not fail")
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil || transport.TLSClientConfig.MaxVersion != tls.VersionTLS12 || len(transport.TLSClientConfig.CipherSuites) == 0 {
+		t.Errorf("client transport = %+v, want the %s profile", client.Transport, defaultTLSProfile)
+	}
+}
@@ -2,138 +2,103 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"io"
-	"log"
+	"errors"
 	"net/http"
-	"strings"
-
-	"golang.org/x/text/encoding/unicode"
-
-	"github.com/danieljoos/wincred"
-	"github.com/lxn/walk"
 )
 
-func (skinLayout *SkinLayout) setData(skinName string, skinUrl string) {
-	if skinUrl != "" {
-		skinLayout.LinkLabel.SetText("<a href=\"" + skinUrl + "\">" + skinName + "</a>")
-	} else {
-		skinLayout.LinkLabel.SetText(skinName)
-	}
-}
-
-func loadSavedUser() (User, error) {
-	var user User
-	cred, err := wincred.GetGenericCredential("ValorantShopwatcher")
-	if err == nil {
-		decoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
-		blob, _ := decoder.Bytes(cred.CredentialBlob)
-		if err != nil {
-			log.Fatal(err)
-		}
-		s := strings.Split(string(blob), "\x00")
-		if !isAccessTokenValid(s[2]) {
-			getAccessToken()
-			user = User{cred.UserName, s[0], s[1], globalStore.User.AccessToken}
-		} else {
-			user = User{cred.UserName, s[0], s[1], s[2]}
-		}
-	}
-	return user, err
-}
-
-func saveUserData(user User) {
-	cred := wincred.NewGenericCredential("ValorantShopwatcher")
-	cred.Persist = wincred.PersistEnterprise
-	cred.TargetAlias = "ValorantShopwatcher"
-	cred.TargetName = "ValorantShopwatcher"
-	encoder := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
-	blob, _ := encoder.Bytes([]byte(user.Password + "\x00" + user.Region + "\x00" + user.AccessToken))
-	cred.CredentialBlob = blob
-	cred.UserName = user.Login
-	err := cred.Write()
-	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not save your credentials", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
-	}
-}
-
-func getAccessToken() {
+func requestAccessToken(ctx context.Context) error {
 	body, _ := json.Marshal(AuthBody{Client_id: "play-valorant-web-prod", Nonce: 1, Redirect_uri: "https://playvalorant.com/opt_in", Response_type: "token id_token", Scope: "account openid"})
 	req, _ := http.NewRequest("POST", "https://auth.riotgames.com/api/v1/authorization", bytes.NewBuffer(body))
 	setRequestHeaders(req)
-	res, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+	if err := doRequest(ctx, req, nil); err != nil {
+		return err
 	}
-	defer res.Body.Close()
 	body, _ = json.Marshal(UserBody{Type: "auth", Username: globalStore.User.Login, Password: globalStore.User.Password})
 	req, _ = http.NewRequest("PUT", "https://auth.riotgames.com/api/v1/authorization", bytes.NewBuffer(body))
 	setRequestHeaders(req)
-	res, err = client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+	var accessTokenContainer AccessTokenContainer
+	if err := doRequest(ctx, req, &accessTokenContainer); err != nil {
+		return err
 	}
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Fatal(err)
+	if accessTokenContainer.Error != "" {
+		return &RiotError{ErrorCode: accessTokenContainer.Error}
 	}
-	defer res.Body.Close()
-	var accessToken string
-	var accessTokenContainer AccessTokenContainer
-	json.Unmarshal(data, &accessTokenContainer)
 	if accessTokenContainer.Type == "response" {
-		accessToken = accessTokenContainer.Response.Parameters.Uri.Query().Get("access_token")
-		globalStore.User.AccessToken = accessToken
+		globalStore.User.AccessToken = accessTokenContainer.Response.Parameters.Uri.Query().Get("access_token")
 	} else if accessTokenContainer.Type == "multifactor" {
-		var mfaResponse MFAResponse
-		json.Unmarshal(data, &mfaResponse)
-		drawMfaModal(globalStore.Ui.mainWindow, mfaResponse.Multifactor.MultiFactorCodeLength)
-	}
-	select {
-	case globalStore.Channels.NewToken <- globalStore.User.AccessToken:
-		return
-	default:
-		return
+		return promptMfaCode(ctx, accessTokenContainer.Multifactor.MultiFactorCodeLength)
+	} else {
+		return &RiotError{Message: "unexpected authorization response " + accessTokenContainer.Type}
 	}
+	return nil
+}
 
+func submitMfaCode(ctx context.Context, code string) error {
+	body, _ := json.Marshal(MFABody{Type: "multifactor", Code: code, RememberDevice: false})
+	req, _ := http.NewRequest("PUT", "https://auth.riotgames.com/api/v1/authorization", bytes.NewBuffer(body))
+	setRequestHeaders(req)
+	var accessTokenContainer AccessTokenContainer
+	err := doRequest(ctx, req, &accessTokenContainer)
+	if err == nil && accessTokenContainer.Error != "" {
+		err = &RiotError{ErrorCode: accessTokenContainer.Error}
+	}
+	if err != nil {
+		return err
+	}
+	globalStore.User.AccessToken = accessTokenContainer.Response.Parameters.Uri.Query().Get("access_token")
+	saveUserData(globalStore.User)
+	return nil
 }
 
-func seedUser() {
-	var err error
-	if globalStore.User.Login == "" {
-		go drawUserform(globalStore.Ui.mainWindow)
-		<-globalStore.Channels.LoginWindow
+func waitForLogin(ctx context.Context) bool {
+	go promptLogin()
+	select {
+	case <-globalStore.Channels.LoginWindow:
+		return true
+	case <-ctx.Done():
+		return false
 	}
-	if !isAccessTokenValid(globalStore.User.AccessToken) {
-		go getAccessToken()
+}
+
+func seedUser(ctx context.Context) {
+	if globalStore.User.Login == "" && !waitForLogin(ctx) {
+		return
 	}
-	<-globalStore.Channels.NewToken
+	valid, err := isAccessTokenValid(ctx, globalStore.User.AccessToken)
 	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not call Riot servers", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		showError(tr("The app could not log you in"), err)
+		return
 	}
-	globalStore.CurrentShop, err = fetchSkinsWithToken(globalStore.User.AccessToken)
+	if !valid {
+		if globalStore.User.Password == "" && !waitForLogin(ctx) {
+			return
+		}
+		if err := requestAccessToken(ctx); err != nil {
+			showError(tr("The app could not log you in"), err)
+			return
+		}
+	}
+	offers, bundles, err := fetchSkinsWithToken(ctx, globalStore.User.AccessToken)
 	if err != nil {
-		walk.MsgBox(nil, "Error", "The app could not fetch skins", walk.MsgBoxIconError)
-		globalStore.Ui.mainWindow.WindowBase.Synchronize(func() {
-			globalStore.Ui.mainWindow.Show()
-		})
+		showError(tr("The app could not fetch skins"), err)
+		return
 	}
+	globalStore.CurrentOffers = offers
+	globalStore.CurrentBundles = bundles
+	globalStore.CurrentShop = dailySkins(offers)
 	drawShop()
 }
 
-func isAccessTokenValid(accessToken string) bool {
+func isAccessTokenValid(ctx context.Context, accessToken string) (bool, error) {
 	req, _ := http.NewRequest("POST", "https://entitlements.auth.riotgames.com/api/token/v1", nil)
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
-	res, err := client.Do(req)
-	if err != nil || res.Status != "200 OK" {
-		return false
+	err := doIdempotentRequest(ctx, req, nil)
+	var riotError *RiotError
+	if errors.As(err, &riotError) && (riotError.StatusCode == http.StatusUnauthorized || riotError.StatusCode == http.StatusForbidden) {
+		return false, nil
 	}
-	return true
+	return err == nil, err
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestIsAccessTokenValid(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantValid bool
+		wantErr   bool
+	}{
+		{"valid", http.StatusOK, true, false},
+		{"expired", http.StatusUnauthorized, false, false},
+		{"revoked", http.StatusForbidden, false, false},
+		{"not found", http.StatusNotFound, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stubClient(t, func(req *http.Request) (*http.Response, error) {
+				if got := req.Header.Get("Authorization"); got != "Bearer token" {
+					t.Errorf("Authorization = %q", got)
+				}
+				return stubResponse(test.status, "{}"), nil
+			})
+			valid, err := isAccessTokenValid(context.Background(), "token")
+			if valid != test.wantValid || (err != nil) != test.wantErr {
+				t.Errorf("isAccessTokenValid() = %v, %v, want %v, error %v", valid, err, test.wantValid, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequestAccessTokenDoesNotRetryCredentials(t *testing.T) {
+	previous := globalStore.User
+	t.Cleanup(func() { globalStore.User = previous })
+	globalStore.User = User{Login: "player", Password: "hunter2"}
+	var puts int
+	stubClient(t, func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut {
+			return stubResponse(http.StatusOK, `{"type":"auth"}`), nil
+		}
+		puts++
+		response := stubResponse(http.StatusTooManyRequests, "{}")
+		response.Header.Set("Retry-After", "0.01")
+		return response, nil
+	})
+	if err := requestAccessToken(context.Background()); !hasStatus(http.StatusTooManyRequests)(err) {
+		t.Errorf("requestAccessToken() error = %v, want HTTP 429", err)
+	}
+	if puts != 1 {
+		t.Errorf("credentials were sent %d times, want 1", puts)
+	}
+}
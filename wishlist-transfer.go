@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const shareCodePrefix = "VSW"
+const shareCodeVersion = 3
+
+var wishlistCsvHeader = []string{"uuid", "name", "chroma", "priority", "max_price"}
+
+type ImportResult struct {
+	Imported     int
+	AlreadyKnown int
+	UnknownIds   []string
+}
+
+func exportWishlist(path string, skins []Skin, rules []WatchRule) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		data, err = encodeWishlistCsv(skins)
+	case ".json":
+		data, err = encodeWishlist(skins, rules)
+	default:
+		return fmt.Errorf("unsupported file type %q, expected .json or .csv", filepath.Ext(path))
+	}
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+func decodeWishlistFile(path string, data []byte) ([]WishlistSkin, []WatchRule, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		skins, err := decodeWishlistCsv(data)
+		return skins, nil, err
+	case ".json":
+		document, _, err := decodeWishlist(data)
+		return document.Skins, document.Rules, err
+	}
+	return nil, nil, fmt.Errorf("unsupported file type %q, expected .json or .csv", filepath.Ext(path))
+}
+
+func encodeWishlistCsv(skins []Skin) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(wishlistCsvHeader); err != nil {
+		return nil, err
+	}
+	for _, skin := range skins {
+		maxPrice := ""
+		if skin.MaxPrice > 0 {
+			maxPrice = strconv.Itoa(skin.MaxPrice)
+		}
+		if err := writer.Write([]string{skin.Id, skin.Name, skin.Chroma, string(skin.Priority), maxPrice}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+func decodeWishlistCsv(data []byte) ([]WishlistSkin, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for index, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	if _, ok := columns["uuid"]; !ok {
+		return nil, errors.New("missing uuid column")
+	}
+	field := func(record []string, name string) string {
+		if index, ok := columns[name]; ok && index < len(record) {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+	var skins []WishlistSkin
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if field(record, "uuid") == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		priority, ok := parsePriority(field(record, "priority"))
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown priority %q", line, field(record, "priority"))
+		}
+		var maxPrice int
+		if value := field(record, "max_price"); value != "" {
+			if maxPrice, err = strconv.Atoi(value); err != nil || maxPrice < 0 {
+				return nil, fmt.Errorf("line %d: invalid max price %q", line, value)
+			}
+		}
+		skins = append(skins, WishlistSkin{Id: field(record, "uuid"), Name: field(record, "name"), Chroma: field(record, "chroma"), Priority: priority, MaxPrice: maxPrice})
+	}
+	return skins, nil
+}
+
+func encodeShareCode(skins []Skin) (string, error) {
+	payload := []byte{shareCodeVersion}
+	for _, skin := range skins {
+		id, err := hex.DecodeString(strings.ReplaceAll(skin.Id, "-", ""))
+		if err != nil || len(id) != 16 {
+			return "", fmt.Errorf("invalid skin id %q", skin.Id)
+		}
+		payload = append(payload, id...)
+		payload = append(payload, byte(priorityIndex(skin.Priority)))
+		maxPrice := clampPrice(skin.MaxPrice)
+		payload = append(payload, byte(maxPrice>>8), byte(maxPrice))
+		if len(skin.Chroma) > math.MaxUint8 {
+			return "", fmt.Errorf("the chroma of skin %q is too long", skin.Id)
+		}
+		payload = append(payload, byte(len(skin.Chroma)))
+		payload = append(payload, skin.Chroma...)
+	}
+	return shareCodePrefix + base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func decodeShareCode(code string) ([]WishlistSkin, error) {
+	code = strings.Join(strings.Fields(code), "")
+	if !strings.HasPrefix(code, shareCodePrefix) {
+		return nil, errors.New("this is not a wishlist code")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, shareCodePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("the wishlist code is damaged: %w", err)
+	}
+	if len(payload) == 0 || payload[0] < 1 || payload[0] > shareCodeVersion {
+		return nil, errors.New("the wishlist code was made by another version of the app")
+	}
+	version := payload[0]
+	entrySize := 16
+	if version >= 2 {
+		entrySize = 19
+	}
+	if version >= 3 {
+		entrySize = 20
+	}
+	payload = payload[1:]
+	var skins []WishlistSkin
+	for len(payload) > 0 {
+		if len(payload) < entrySize {
+			return nil, errors.New("the wishlist code is damaged")
+		}
+		skin := WishlistSkin{Id: formatUuid(payload[:16])}
+		if version >= 2 {
+			if int(payload[16]) >= len(priorities) {
+				return nil, errors.New("the wishlist code is damaged")
+			}
+			skin.Priority = priorities[payload[16]]
+			skin.MaxPrice = int(binary.BigEndian.Uint16(payload[17:19]))
+		}
+		size := entrySize
+		if version >= 3 {
+			size += int(payload[19])
+			if len(payload) < size {
+				return nil, errors.New("the wishlist code is damaged")
+			}
+			skin.Chroma = string(payload[entrySize:size])
+		}
+		skins = append(skins, skin)
+		payload = payload[size:]
+	}
+	return skins, nil
+}
+
+func priorityIndex(priority Priority) int {
+	for index, candidate := range priorities {
+		if candidate == priority.OrDefault() {
+			return index
+		}
+	}
+	return 1
+}
+
+func clampPrice(price int) int {
+	if price < 0 {
+		return 0
+	}
+	if price > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return price
+}
+
+func formatUuid(id []byte) string {
+	encoded := strings.ToUpper(hex.EncodeToString(id))
+	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:32]
+}
+
+func findSkinById(skins []Skin, id string) (Skin, bool) {
+	for _, skin := range skins {
+		if strings.EqualFold(skin.Id, id) {
+			return skin, true
+		}
+	}
+	return Skin{}, false
+}
+
+func importRules(rules []WatchRule, current []WatchRule, replace bool) ([]WatchRule, int) {
+	if rules == nil {
+		return current, 0
+	}
+	var merged []WatchRule
+	if !replace {
+		merged = append(merged, current...)
+	}
+	imported := 0
+	for _, rule := range rules {
+		known := false
+		for _, existing := range merged {
+			if existing.Name == rule.Name && existing.Expression == rule.Expression {
+				known = true
+				break
+			}
+		}
+		if !known {
+			merged = append(merged, rule)
+			imported++
+		}
+	}
+	return merged, imported
+}
+
+func importWishlist(entries []WishlistSkin, catalog []Skin, current []Skin, replace bool) ([]Skin, ImportResult, error) {
+	var result ImportResult
+	if len(catalog) == 0 {
+		return current, result, errors.New("the skin list has not been loaded yet, please try again in a moment")
+	}
+	var skins []Skin
+	if !replace {
+		skins = append(skins, current...)
+	}
+	for _, entry := range entries {
+		skin, ok := findSkinById(catalog, entry.Id)
+		if !ok {
+			result.UnknownIds = append(result.UnknownIds, entry.Id)
+			continue
+		}
+		if _, ok := findSkinById(skins, skin.Id); ok {
+			result.AlreadyKnown++
+			continue
+		}
+		skin.Chroma = entry.Chroma
+		skin.Priority, _ = parsePriority(string(entry.Priority))
+		skin.MaxPrice = entry.MaxPrice
+		skins = append(skins, skin)
+		result.Imported++
+	}
+	return skins, result, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestDecodeWishlistCsv(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []WishlistSkin
+		wantErr string
+	}{
+		{"all columns", "uuid,name,chroma,priority,max_price\na,Prime Vandal,red,must-have,1775\n", []WishlistSkin{{Id: "a", Name: "Prime Vandal", Chroma: "red", Priority: PriorityMustHave, MaxPrice: 1775}}, ""},
+		{"reordered columns", "Name, UUID\nPrime Vandal, a\n", []WishlistSkin{{Id: "a", Name: "Prime Vandal", Priority: PriorityNiceToHave}}, ""},
+		{"rows without uuid are skipped", "uuid,name\n,nothing\nb,Reaver Sheriff\n", []WishlistSkin{{Id: "b", Name: "Reaver Sheriff", Priority: PriorityNiceToHave}}, ""},
+		{"missing uuid column", "name\nPrime Vandal\n", nil, "missing uuid column"},
+		{"unknown priority after skipped rows", "uuid,priority\n,\n\na,must\nb,urgent\n", nil, "line 5: unknown priority"},
+		{"invalid max price after a multiline name", "uuid,name,max_price\na,\"Prime\nVandal\",10\nb,Reaver,-1\n", nil, "line 4: invalid max price"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skins, err := decodeWishlistCsv([]byte(test.data))
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("decodeWishlistCsv() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeWishlistCsv() error = %v", err)
+			}
+			if len(skins) != len(test.want) {
+				t.Fatalf("decodeWishlistCsv() = %+v, want %+v", skins, test.want)
+			}
+			for index := range skins {
+				if skins[index].Id != test.want[index].Id || skins[index].Name != test.want[index].Name || skins[index].Chroma != test.want[index].Chroma || skins[index].Priority != test.want[index].Priority || skins[index].MaxPrice != test.want[index].MaxPrice {
+					t.Errorf("decodeWishlistCsv()[%d] = %+v, want %+v", index, skins[index], test.want[index])
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeWishlistCsvRoundTrip(t *testing.T) {
+	skins := []Skin{{Id: "a", Name: "Prime, Vandal", Priority: PriorityWatching, MaxPrice: 2175}, {Id: "b", Name: "Reaver Sheriff", Chroma: "black"}}
+	data, err := encodeWishlistCsv(skins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeWishlistCsv(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0].Name != "Prime, Vandal" || decoded[0].Priority != PriorityWatching || decoded[0].MaxPrice != 2175 || decoded[1].Chroma != "black" || decoded[1].Priority != PriorityNiceToHave {
+		t.Errorf("decodeWishlistCsv(encodeWishlistCsv()) = %+v", decoded)
+	}
+}
+
+func TestShareCodeRoundTrip(t *testing.T) {
+	skins := []Skin{
+		{Id: "2f0c8b3a-4d4e-3b9b-8d1a-66c5f2a1a2b3", Priority: PriorityMustHave, MaxPrice: 1775, Chroma: "Variant 2 Red"},
+		{Id: "00000000-0000-0000-0000-0000000000ff", MaxPrice: 100000},
+		{Id: "ffffffff-ffff-ffff-ffff-ffffffffffff", Priority: PriorityWatching},
+	}
+	code, err := encodeShareCode(skins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeShareCode(" " + code[:10] + "\n" + code[10:] + " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []WishlistSkin{
+		{Id: "2F0C8B3A-4D4E-3B9B-8D1A-66C5F2A1A2B3", Priority: PriorityMustHave, MaxPrice: 1775, Chroma: "Variant 2 Red"},
+		{Id: "00000000-0000-0000-0000-0000000000FF", Priority: PriorityNiceToHave, MaxPrice: 65535},
+		{Id: "FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF", Priority: PriorityWatching},
+	}
+	if len(decoded) != len(want) {
+		t.Fatalf("decodeShareCode() = %+v, want %+v", decoded, want)
+	}
+	for index := range want {
+		if decoded[index].Id != want[index].Id || decoded[index].Priority != want[index].Priority || decoded[index].MaxPrice != want[index].MaxPrice || decoded[index].Chroma != want[index].Chroma {
+			t.Errorf("decodeShareCode()[%d] = %+v, want %+v", index, decoded[index], want[index])
+		}
+	}
+}
+
+func TestDecodeShareCode(t *testing.T) {
+	id := make([]byte, 16)
+	id[15] = 1
+	versionOne := shareCodePrefix + base64.RawURLEncoding.EncodeToString(append([]byte{1}, id...))
+	versionTwo := shareCodePrefix + base64.RawURLEncoding.EncodeToString(append(append([]byte{2}, id...), 0, 0, 100))
+	tests := []struct {
+		name    string
+		code    string
+		wantIds []string
+		wantErr string
+	}{
+		{"version 1", versionOne, []string{"00000000-0000-0000-0000-000000000001"}, ""},
+		{"version 2", versionTwo, []string{"00000000-0000-0000-0000-000000000001"}, ""},
+		{"empty wishlist", shareCodePrefix + base64.RawURLEncoding.EncodeToString([]byte{shareCodeVersion}), nil, ""},
+		{"missing prefix", "ABC", nil, "not a wishlist code"},
+		{"not base64", shareCodePrefix + "!!!", nil, "damaged"},
+		{"newer version", shareCodePrefix + base64.RawURLEncoding.EncodeToString([]byte{shareCodeVersion + 1}), nil, "another version"},
+		{"truncated entry", shareCodePrefix + base64.RawURLEncoding.EncodeToString(append([]byte{2}, id...)), nil, "damaged"},
+		{"unknown priority", shareCodePrefix + base64.RawURLEncoding.EncodeToString(append(append([]byte{2}, id...), 9, 0, 0)), nil, "damaged"},
+		{"truncated chroma", shareCodePrefix + base64.RawURLEncoding.EncodeToString(append(append([]byte{3}, id...), 0, 0, 0, 4, 'r', 'e', 'd')), nil, "damaged"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skins, err := decodeShareCode(test.code)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("decodeShareCode() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeShareCode() error = %v", err)
+			}
+			if len(skins) != len(test.wantIds) {
+				t.Fatalf("decodeShareCode() = %+v, want %v", skins, test.wantIds)
+			}
+			for index, id := range test.wantIds {
+				if skins[index].Id != id {
+					t.Errorf("decodeShareCode()[%d] = %q, want %q", index, skins[index].Id, id)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeShareCodeRejectsInvalidIds(t *testing.T) {
+	for _, id := range []string{"", "not-a-uuid", "2f0c8b3a-4d4e-3b9b-8d1a"} {
+		if _, err := encodeShareCode([]Skin{{Id: id}}); err == nil {
+			t.Errorf("encodeShareCode(%q) succeeded", id)
+		}
+	}
+	if _, err := encodeShareCode([]Skin{{Id: "2f0c8b3a-4d4e-3b9b-8d1a-66c5f2a1a2b3", Chroma: strings.Repeat("x", 256)}}); err == nil {
+		t.Error("encodeShareCode() with a 256 byte chroma succeeded")
+	}
+}
+
+func TestDecodeWishlistFile(t *testing.T) {
+	rules := []WatchRule{{Name: "exclusive", Expression: "tier == 'Exclusive'", Priority: PriorityWatching}}
+	document, err := encodeWishlist([]Skin{WishlistSkin{Id: "a", Name: "Prime Vandal"}.Skin()}, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path      string
+		data      []byte
+		wantIds   []string
+		wantRules []WatchRule
+		wantErr   bool
+	}{
+		{"wishlist.json", document, []string{"a"}, rules, false},
+		{"wishlist.CSV", []byte("uuid\nb\n"), []string{"b"}, nil, false},
+		{"wishlist.txt", []byte("b"), nil, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			skins, gotRules, err := decodeWishlistFile(test.path, test.data)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("decodeWishlistFile() error = %v", err)
+			}
+			var ids []string
+			for _, skin := range skins {
+				ids = append(ids, skin.Id)
+			}
+			if strings.Join(ids, ",") != strings.Join(test.wantIds, ",") {
+				t.Errorf("decodeWishlistFile() skins = %v, want %v", ids, test.wantIds)
+			}
+			for index := range gotRules {
+				gotRules[index].expression = nil
+			}
+			if len(gotRules) != len(test.wantRules) || len(gotRules) > 0 && gotRules[0] != test.wantRules[0] {
+				t.Errorf("decodeWishlistFile() rules = %+v, want %+v", gotRules, test.wantRules)
+			}
+		})
+	}
+}
+
+func TestImportRules(t *testing.T) {
+	current := []WatchRule{{Name: "exclusive", Expression: "tier == 'Exclusive'"}}
+	imported := []WatchRule{{Name: "exclusive", Expression: "tier == 'Exclusive'"}, {Name: "cheap", Expression: "price < 1000"}}
+	tests := []struct {
+		name         string
+		rules        []WatchRule
+		replace      bool
+		wantNames    []string
+		wantImported int
+	}{
+		{"add", imported, false, []string{"exclusive", "cheap"}, 1},
+		{"replace", imported, true, []string{"exclusive", "cheap"}, 2},
+		{"replace with an empty list", []WatchRule{}, true, nil, 0},
+		{"file without rules", nil, true, []string{"exclusive"}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, count := importRules(test.rules, current, test.replace)
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(test.wantNames, ",") || count != test.wantImported {
+				t.Errorf("importRules() = %v, %d, want %v, %d", names, count, test.wantNames, test.wantImported)
+			}
+		})
+	}
+}
+
+func TestImportWishlist(t *testing.T) {
+	catalog := []Skin{{Id: "A", Name: "Prime Vandal"}, {Id: "B", Name: "Reaver Sheriff"}, {Id: "C", Name: "Ion Phantom"}}
+	current := []Skin{{Id: "A", Name: "Prime Vandal"}}
+	entries := []WishlistSkin{{Id: "a"}, {Id: "b", Priority: PriorityMustHave, MaxPrice: 1775}, {Id: "z"}}
+	tests := []struct {
+		name    string
+		replace bool
+		wantIds []string
+		want    ImportResult
+	}{
+		{"add", false, []string{"A", "B"}, ImportResult{Imported: 1, AlreadyKnown: 1, UnknownIds: []string{"z"}}},
+		{"replace", true, []string{"A", "B"}, ImportResult{Imported: 2, UnknownIds: []string{"z"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skins, result, err := importWishlist(entries, catalog, current, test.replace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Imported != test.want.Imported || result.AlreadyKnown != test.want.AlreadyKnown || strings.Join(result.UnknownIds, ",") != strings.Join(test.want.UnknownIds, ",") {
+				t.Errorf("importWishlist() result = %+v, want %+v", result, test.want)
+			}
+			var ids []string
+			for _, skin := range skins {
+				ids = append(ids, skin.Id)
+			}
+			if strings.Join(ids, ",") != strings.Join(test.wantIds, ",") {
+				t.Errorf("importWishlist() skins = %v, want %v", ids, test.wantIds)
+			}
+			if skins[1].Priority != PriorityMustHave || skins[1].MaxPrice != 1775 {
+				t.Errorf("importWishlist() skin = %+v", skins[1])
+			}
+		})
+	}
+	if _, _, err := importWishlist(entries, nil, current, false); err == nil {
+		t.Error("importWishlist() without a catalog succeeded")
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const wishlistVersion = 2
+
+type WishlistDocument struct {
+	Version int            `json:"version"`
+	SavedAt time.Time      `json:"savedAt"`
+	Skins   []WishlistSkin `json:"skins"`
+	Rules   []WatchRule    `json:"rules"`
+}
+
+type WishlistSkin struct {
+	Id             string            `json:"id"`
+	Name           string            `json:"name"`
+	LocalizedNames map[string]string `json:"localizedNames"`
+	AssetName      string            `json:"assetName,omitempty"`
+	AssetPath      string            `json:"assetPath,omitempty"`
+	Chroma         string            `json:"chroma,omitempty"`
+	Priority       Priority          `json:"priority,omitempty"`
+	MaxPrice       int               `json:"maxPrice,omitempty"`
+}
+
+type wishlistHeader struct {
+	Version *int `json:"version"`
+}
+
+var wishlistMigrations = []func(data []byte) ([]byte, error){
+	migrateWishlistFromArray,
+	migrateWishlistAddRules,
+}
+
+func migrateWishlistFromArray(data []byte) ([]byte, error) {
+	var skins []struct {
+		Id             string
+		Name           string
+		LocalizedNames map[string]string
+		AssetName      string
+		AssetPath      string
+	}
+	if err := json.Unmarshal(data, &skins); err != nil {
+		return nil, err
+	}
+	document := WishlistDocument{Version: 1, Skins: make([]WishlistSkin, 0, len(skins))}
+	for _, skin := range skins {
+		document.Skins = append(document.Skins, WishlistSkin{
+			Id:             skin.Id,
+			Name:           skin.Name,
+			LocalizedNames: skin.LocalizedNames,
+			AssetName:      skin.AssetName,
+			AssetPath:      skin.AssetPath,
+		})
+	}
+	return json.Marshal(document)
+}
+
+func migrateWishlistAddRules(data []byte) ([]byte, error) {
+	var document WishlistDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	document.Version = 2
+	document.Rules = []WatchRule{}
+	return json.Marshal(document)
+}
+
+func wishlistDocumentVersion(data []byte) (int, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return 0, nil
+	}
+	var header wishlistHeader
+	if err := json.Unmarshal(trimmed, &header); err != nil {
+		return 0, err
+	}
+	if header.Version == nil {
+		return 0, errors.New("missing version header")
+	}
+	return *header.Version, nil
+}
+
+func decodeWishlist(data []byte) (WishlistDocument, int, error) {
+	var document WishlistDocument
+	version, err := wishlistDocumentVersion(data)
+	if err != nil {
+		return document, 0, err
+	}
+	if version > wishlistVersion {
+		return document, version, fmt.Errorf("version %d was written by a newer version of the app", version)
+	}
+	if version < 0 {
+		return document, version, fmt.Errorf("invalid version %d", version)
+	}
+	migrated := data
+	for v := version; v < wishlistVersion; v++ {
+		if migrated, err = wishlistMigrations[v](migrated); err != nil {
+			return document, version, fmt.Errorf("migrating from version %d: %w", v, err)
+		}
+	}
+	if err := json.Unmarshal(migrated, &document); err != nil {
+		return document, version, err
+	}
+	return document, version, document.Validate()
+}
+
+func (document *WishlistDocument) Validate() error {
+	var problems []string
+	seen := make(map[string]bool)
+	for index, skin := range document.Skins {
+		switch {
+		case skin.Id == "":
+			problems = append(problems, fmt.Sprintf("skin #%d has no id", index+1))
+		case seen[skin.Id]:
+			problems = append(problems, fmt.Sprintf("skin %s is listed twice", skin.Id))
+		case skin.Name == "" && len(skin.LocalizedNames) == 0:
+			problems = append(problems, fmt.Sprintf("skin %s has no name", skin.Id))
+		case skin.MaxPrice < 0:
+			problems = append(problems, fmt.Sprintf("skin %s has a negative max price", skin.Id))
+		}
+		if _, ok := parsePriority(string(skin.Priority)); !ok {
+			problems = append(problems, fmt.Sprintf("skin %s has an unknown priority %q", skin.Id, skin.Priority))
+		}
+		seen[skin.Id] = true
+	}
+	for index := range document.Rules {
+		rule := &document.Rules[index]
+		if err := rule.precompile(); err != nil {
+			problems = append(problems, fmt.Sprintf("rule #%d (%s) is invalid: %v", index+1, rule.Name, err))
+		}
+		if _, ok := parsePriority(string(rule.Priority)); !ok {
+			problems = append(problems, fmt.Sprintf("rule #%d (%s) has an unknown priority %q", index+1, rule.Name, rule.Priority))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func encodeWishlist(skins []Skin, rules []WatchRule) ([]byte, error) {
+	document := WishlistDocument{Version: wishlistVersion, SavedAt: time.Now().UTC(), Skins: make([]WishlistSkin, 0, len(skins)), Rules: rules}
+	if document.Rules == nil {
+		document.Rules = []WatchRule{}
+	}
+	for _, skin := range skins {
+		document.Skins = append(document.Skins, newWishlistSkin(skin))
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func newWishlistSkin(skin Skin) WishlistSkin {
+	localizedNames := make(map[string]string)
+	if skin.LocalizedNames.Map != nil {
+		skin.LocalizedNames.Range(func(key any, value any) bool {
+			localizedNames[key.(string)] = value.(string)
+			return true
+		})
+	}
+	return WishlistSkin{Id: skin.Id, Name: skin.Name, LocalizedNames: localizedNames, AssetName: skin.AssetName, AssetPath: skin.AssetPath, Chroma: skin.Chroma, Priority: skin.Priority, MaxPrice: skin.MaxPrice}
+}
+
+func (wishlistSkin WishlistSkin) Skin() Skin {
+	localizedNames := SynchronizedMap{&sync.Map{}}
+	for key, value := range wishlistSkin.LocalizedNames {
+		localizedNames.Store(key, value)
+	}
+	if _, ok := localizedNames.Load("en-US"); !ok && wishlistSkin.Name != "" {
+		localizedNames.Store("en-US", wishlistSkin.Name)
+	}
+	priority, _ := parsePriority(string(wishlistSkin.Priority))
+	return Skin{Id: wishlistSkin.Id, Name: wishlistSkin.Name, LocalizedNames: localizedNames, AssetName: wishlistSkin.AssetName, AssetPath: wishlistSkin.AssetPath, Chroma: wishlistSkin.Chroma, Priority: priority, MaxPrice: wishlistSkin.MaxPrice}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeWishlist(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantVersion int
+		wantSkins   []string
+		wantRules   int
+		wantErr     string
+	}{
+		{"legacy array", `[{"Id":"a","Name":"Prime Vandal","LocalizedNames":{"fr-FR":"Vandal Prime"},"AssetName":"asset"}]`, 0, []string{"a"}, 0, ""},
+		{"empty legacy array", `[]`, 0, nil, 0, ""},
+		{"version 1", `{"version":1,"skins":[{"id":"a","name":"Prime Vandal"},{"id":"b","name":"Reaver Sheriff"}]}`, 1, []string{"a", "b"}, 0, ""},
+		{"version 2", `{"version":2,"skins":[{"id":"a","name":"Prime Vandal","priority":"must-have"}],"rules":[{"name":"prime","expression":"collection == 'Prime'"}]}`, 2, []string{"a"}, 1, ""},
+		{"newer version", `{"version":3,"skins":[]}`, 3, nil, 0, "newer version"},
+		{"negative version", `{"version":-1,"skins":[]}`, -1, nil, 0, "invalid version"},
+		{"missing version", `{"skins":[]}`, 0, nil, 0, "missing version"},
+		{"not json", `skins`, 0, nil, 0, "invalid character"},
+		{"duplicate skin", `{"version":2,"skins":[{"id":"a","name":"x"},{"id":"a","name":"x"}],"rules":[]}`, 2, nil, 0, "listed twice"},
+		{"unknown priority", `{"version":2,"skins":[{"id":"a","name":"x","priority":"urgent"}],"rules":[]}`, 2, nil, 0, "unknown priority"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document, version, err := decodeWishlist([]byte(test.data))
+			if version != test.wantVersion {
+				t.Errorf("decodeWishlist() version = %d, want %d", version, test.wantVersion)
+			}
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("decodeWishlist() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeWishlist() error = %v", err)
+			}
+			if len(document.Skins) != len(test.wantSkins) {
+				t.Fatalf("decodeWishlist() skins = %+v, want %v", document.Skins, test.wantSkins)
+			}
+			for index, id := range test.wantSkins {
+				if document.Skins[index].Id != id {
+					t.Errorf("decodeWishlist() skin #%d = %q, want %q", index, document.Skins[index].Id, id)
+				}
+			}
+			if len(document.Rules) != test.wantRules {
+				t.Errorf("decodeWishlist() rules = %+v, want %d", document.Rules, test.wantRules)
+			}
+		})
+	}
+}
+
+func TestMigrateWishlistFromArrayKeepsNames(t *testing.T) {
+	document, _, err := decodeWishlist([]byte(`[{"Id":"a","Name":"Prime Vandal","LocalizedNames":{"fr-FR":"Vandal Prime"},"AssetPath":"path"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skin := document.Skins[0].Skin()
+	if name, _ := skin.LocalizedNames.Load("fr-FR"); name != "Vandal Prime" {
+		t.Errorf("fr-FR name = %v, want Vandal Prime", name)
+	}
+	if name, _ := skin.LocalizedNames.Load("en-US"); name != "Prime Vandal" {
+		t.Errorf("en-US name = %v, want Prime Vandal", name)
+	}
+	if skin.AssetPath != "path" || skin.Priority != PriorityNiceToHave {
+		t.Errorf("Skin() = %+v", skin)
+	}
+}
+
+func TestEncodeWishlistRoundTrip(t *testing.T) {
+	skins := []Skin{
+		WishlistSkin{Id: "a", Name: "Prime Vandal", Priority: PriorityMustHave, MaxPrice: 1775}.Skin(),
+		WishlistSkin{Id: "b", Name: "Reaver Sheriff", Chroma: "red"}.Skin(),
+	}
+	rules := []WatchRule{{Name: "exclusive", Expression: "tier == 'Exclusive'", Priority: PriorityWatching}}
+	data, err := encodeWishlist(skins, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, version, err := decodeWishlist(data)
+	if err != nil || version != wishlistVersion {
+		t.Fatalf("decodeWishlist() = %d, %v", version, err)
+	}
+	if len(document.Skins) != 2 || document.Skins[0].Priority != PriorityMustHave || document.Skins[0].MaxPrice != 1775 || document.Skins[1].Chroma != "red" {
+		t.Errorf("decodeWishlist() skins = %+v", document.Skins)
+	}
+	if len(document.Rules) != 1 || document.Rules[0].expression == nil {
+		t.Fatalf("decodeWishlist() rules = %+v, want them compiled", document.Rules)
+	}
+	decoded := document.Rules[0]
+	decoded.expression = nil
+	if decoded != rules[0] {
+		t.Errorf("decodeWishlist() rules = %+v", document.Rules)
+	}
+}